@@ -1,17 +1,24 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"hi-go/src/config"
 	"hi-go/src/model"
 	"hi-go/src/router"
+	"hi-go/src/service"
 	"hi-go/src/service/aiservice"
+	"hi-go/src/service/webhook"
+	"hi-go/src/utils/elasticsearch"
 	"hi-go/src/utils/jwt"
 	"hi-go/src/utils/logger"
+	"hi-go/src/utils/mailer"
 	"hi-go/src/utils/mysql"
+	"hi-go/src/utils/passwordpolicy"
 	"hi-go/src/utils/redis"
 	"hi-go/src/utils/snowflake"
 	"hi-go/src/utils/yapi"
+	"os"
 
 	"go.uber.org/zap"
 )
@@ -49,7 +56,9 @@ func initConfig() {
 
 // initJWT 初始化JWT管理器
 func initJWT() {
-	jwt.Init(nil)
+	if err := jwt.Init(nil); err != nil {
+		logger.Fatalf("JWT初始化失败: %v", err)
+	}
 }
 
 // initLogger 初始化日志
@@ -144,14 +153,119 @@ func initSnowflake() {
 	logger.Info("雪花ID生成器初始化成功", zap.Int64("machineID", machineID))
 }
 
+// initMailer 初始化邮件服务
+func initMailer() {
+	mailCfg := config.Config.Mail
+	cfg := &mailer.Config{
+		Driver:   mailCfg.Driver,
+		From:     mailCfg.From,
+		Host:     mailCfg.SMTPHost,
+		Port:     mailCfg.SMTPPort,
+		Username: mailCfg.SMTPUsername,
+		Password: mailCfg.SMTPPassword,
+		FilePath: mailCfg.FilePath,
+	}
+
+	if err := mailer.Init(cfg); err != nil {
+		logger.Error("邮件服务初始化失败", zap.Error(err))
+		panic(err)
+	}
+	logger.Info("邮件服务初始化成功", zap.String("driver", cfg.Driver))
+}
+
+// initPasswordPolicy 初始化密码策略
+func initPasswordPolicy() {
+	policyCfg := config.Config.Password
+	cfg := &passwordpolicy.Config{
+		MinLength:      config.Config.Business.PasswordMinLength,
+		MaxLength:      policyCfg.MaxLength,
+		MinCharClasses: policyCfg.MinCharClasses,
+		CheckUserInfo:  policyCfg.CheckUserInfo,
+		BlocklistFile:  policyCfg.BlocklistFile,
+	}
+
+	if err := passwordpolicy.Init(cfg); err != nil {
+		logger.Error("密码策略初始化失败", zap.Error(err))
+		panic(err)
+	}
+	logger.Info("密码策略初始化成功", zap.Int("blocklist", passwordpolicy.Default.BlocklistSize()))
+}
+
+// initSearch 初始化全文检索（Elasticsearch）
+// 连接失败不影响服务启动，搜索接口会回退到 MySQL
+func initSearch() {
+	searchCfg := config.Config.Search
+	if !searchCfg.Enabled {
+		return
+	}
+	cfg := &elasticsearch.ClientConfig{
+		Addrs:    searchCfg.Addrs,
+		Username: searchCfg.Username,
+		Password: searchCfg.Password,
+		MaxRetry: config.Config.Elasticsearch.MaxRetry,
+	}
+	if len(cfg.Addrs) == 0 {
+		cfg.Addrs = config.Config.Elasticsearch.Addrs
+		cfg.Username = config.Config.Elasticsearch.Username
+		cfg.Password = config.Config.Elasticsearch.Password
+	}
+
+	if err := elasticsearch.InitClient(cfg); err != nil {
+		logger.Warn("全文检索初始化失败，搜索将使用 MySQL", zap.Error(err))
+		return
+	}
+	if err := service.NewHomeSearcher().EnsureIndex(context.Background()); err != nil {
+		logger.Warn("首页搜索索引初始化失败", zap.String("index", searchCfg.HomeIndex), zap.Error(err))
+		return
+	}
+	logger.Info("全文检索初始化成功", zap.Strings("addrs", cfg.Addrs), zap.String("index", searchCfg.HomeIndex))
+}
+
+// reindexHome 重建首页内容搜索索引（命令：hi-go reindex-home）
+func reindexHome() {
+	if elasticsearch.Client == nil {
+		logger.Fatalf("重建索引失败: 全文检索未启用或 Elasticsearch 不可用")
+	}
+	count, err := service.NewHomeSearcher().Reindex(context.Background())
+	if err != nil {
+		logger.Fatalf("重建索引失败: %v", err)
+	}
+	logger.Info("重建首页搜索索引完成", zap.Int("count", count), zap.String("index", config.Config.Search.HomeIndex))
+}
+
 // initDB 初始化数据库（迁移表结构）
 func initDB() {
 	// 自动迁移数据库表
-	if err := mysql.Database.AutoMigrate(&model.User{}, &model.Home{}, &model.Webhook{}); err != nil {
+	if err := mysql.Database.AutoMigrate(
+		&model.User{}, &model.Home{}, &model.HomeSlot{}, &model.HomeTag{}, &model.HomeRevision{}, &model.HomeDailyStat{}, &model.Webhook{},
+		&model.Role{}, &model.Permission{}, &model.UserRole{},
+		&model.UserTwoFactor{}, &model.APIKey{}, &model.UserIdentity{},
+		&model.AuditLog{},
+	); err != nil {
 		logger.Error("数据库迁移失败", zap.Error(err))
 		panic(err)
 	}
 	logger.Info("数据库迁移成功")
+
+	// 初始化内置角色和权限
+	if err := service.NewRoleService().SeedDefaults(); err != nil {
+		logger.Error("角色权限初始化失败", zap.Error(err))
+		panic(err)
+	}
+	logger.Info("角色权限初始化成功")
+}
+
+// initJobs 启动后台任务
+func initJobs() {
+	ctx := context.Background()
+	// 回收站自动清理
+	service.StartHomeTrashPurger(ctx)
+	// 首页内容定时上下线
+	service.StartHomePublishScheduler(ctx)
+	// 首页曝光点击统计写入
+	service.StartHomeStatsFlusher(ctx)
+	// 出站 webhook 投递
+	webhook.StartDispatcher(ctx)
 }
 
 // initYApiSync 同步 Swagger 文档到 YApi
@@ -192,21 +306,40 @@ func main() {
 	// 4. 初始化MySQL
 	initMySQL()
 
+	// 重建搜索索引命令，执行完成后退出
+	if len(os.Args) > 1 && os.Args[1] == "reindex-home" {
+		initSearch()
+		reindexHome()
+		return
+	}
+
 	// 5. 初始化Redis
 	initRedis()
 
 	// 6. 初始化雪花ID生成器
 	initSnowflake()
 
-	// 7. 初始化数据库（迁移表结构）
+	// 7. 初始化邮件服务
+	initMailer()
+
+	// 8. 初始化密码策略
+	initPasswordPolicy()
+
+	// 9. 初始化数据库（迁移表结构）
 	initDB()
 
-	// 8. 初始化AI服务
+	// 10. 初始化全文检索
+	initSearch()
+
+	// 11. 初始化AI服务
 	aiservice.Init()
 
-	// 9. 同步 Swagger 文档到 YApi（可选）
+	// 12. 启动后台任务
+	initJobs()
+
+	// 13. 同步 Swagger 文档到 YApi（可选）
 	initYApiSync()
 
-	// 10. 设置路由并启动服务
+	// 14. 设置路由并启动服务
 	initRouter()
 }
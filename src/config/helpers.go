@@ -12,6 +12,11 @@ func GetJWTRefreshTokenDuration() time.Duration {
 	return time.Duration(Config.JWT.RefreshTokenDuration) * time.Second
 }
 
+// GetJWTImpersonationTTL 获取管理员代登录令牌有效期
+func GetJWTImpersonationTTL() time.Duration {
+	return time.Duration(Config.JWT.ImpersonationTTL) * time.Second
+}
+
 // GetRedisTokenTTL 获取 Redis Token TTL
 func GetRedisTokenTTL() time.Duration {
 	return time.Duration(Config.Redis.TokenTTL) * time.Second
@@ -22,6 +27,86 @@ func GetRedisSessionTTL() time.Duration {
 	return time.Duration(Config.Redis.SessionTTL) * time.Second
 }
 
+// GetLoginFailureWindow 获取登录失败次数统计窗口
+func GetLoginFailureWindow() time.Duration {
+	return time.Duration(Config.Business.LoginFailureWindow) * time.Second
+}
+
+// GetLoginLockoutDuration 获取登录锁定时长
+func GetLoginLockoutDuration() time.Duration {
+	return time.Duration(Config.Business.LoginLockoutDuration) * time.Second
+}
+
+// GetTwoFactorChallengeTTL 获取两步验证登录挑战有效期
+func GetTwoFactorChallengeTTL() time.Duration {
+	return time.Duration(Config.TwoFactor.ChallengeTTL) * time.Second
+}
+
+// GetMailResetTokenTTL 获取重置密码令牌有效期
+func GetMailResetTokenTTL() time.Duration {
+	return time.Duration(Config.Mail.ResetTokenTTL) * time.Second
+}
+
+// GetMailVerifyTokenTTL 获取邮箱验证令牌有效期
+func GetMailVerifyTokenTTL() time.Duration {
+	return time.Duration(Config.Mail.VerifyTokenTTL) * time.Second
+}
+
+// GetOAuthStateTTL 获取第三方登录授权请求有效期
+func GetOAuthStateTTL() time.Duration {
+	return time.Duration(Config.OAuth.StateTTL) * time.Second
+}
+
+// GetHomeTrashRetention 获取首页内容在回收站中的保留时长
+func GetHomeTrashRetention() time.Duration {
+	return time.Duration(Config.Home.TrashRetentionDays) * 24 * time.Hour
+}
+
+// GetHomeTrashPurgeInterval 获取回收站清理任务执行间隔
+func GetHomeTrashPurgeInterval() time.Duration {
+	return time.Duration(Config.Home.TrashPurgeInterval) * time.Second
+}
+
+// GetHomePublishCheckInterval 获取定时上下线任务的最长检查间隔
+func GetHomePublishCheckInterval() time.Duration {
+	return time.Duration(Config.Home.PublishCheckInterval) * time.Second
+}
+
+// GetHomeCacheTTL 获取首页列表和详情的缓存时长
+func GetHomeCacheTTL() time.Duration {
+	return time.Duration(Config.Home.CacheTTL) * time.Second
+}
+
+// GetHomeStatsFlushInterval 获取曝光点击计数写入数据库的间隔
+func GetHomeStatsFlushInterval() time.Duration {
+	return time.Duration(Config.Home.StatsFlushInterval) * time.Second
+}
+
+// GetSearchTimeout 获取全文检索查询超时时间
+func GetSearchTimeout() time.Duration {
+	return time.Duration(Config.Search.Timeout) * time.Second
+}
+
+// GetWebhookPollInterval 获取 webhook 投递队列的轮询间隔
+func GetWebhookPollInterval() time.Duration {
+	return time.Duration(Config.Webhook.PollInterval) * time.Second
+}
+
+// GetWebhookDeliveryTimeout 获取 webhook 单次投递的请求超时
+func GetWebhookDeliveryTimeout() time.Duration {
+	return time.Duration(Config.Webhook.DeliveryTimeout) * time.Second
+}
+
+// GetWebhookRetryBaseDelay 获取 webhook 首次重试的基础间隔
+func GetWebhookRetryBaseDelay() time.Duration {
+	return time.Duration(Config.Webhook.RetryBaseDelay) * time.Second
+}
+
+// GetWebhookRetryMaxDelay 获取 webhook 重试间隔上限
+func GetWebhookRetryMaxDelay() time.Duration {
+	return time.Duration(Config.Webhook.RetryMaxDelay) * time.Second
+}
+
 // GetDBConnMaxLifetime 获取数据库连接最大生命周期
 func GetDBConnMaxLifetime() time.Duration {
 	return time.Duration(Config.Database.ConnMaxLifetime) * time.Second
@@ -13,6 +13,14 @@ type AppConfig struct {
 	Logstash      LogstashConfig      `mapstructure:"logstash"`
 	YApi          YApiConfig          `mapstructure:"yapi"`
 	AI            AIConfig            `mapstructure:"ai"`
+	RBAC          RBACConfig          `mapstructure:"rbac"`
+	TwoFactor     TwoFactorConfig     `mapstructure:"two_factor"`
+	Mail          MailConfig          `mapstructure:"mail"`
+	OAuth         OAuthConfig         `mapstructure:"oauth"`
+	Password      PasswordConfig      `mapstructure:"password_policy"`
+	Home          HomeConfig          `mapstructure:"home"`
+	Search        SearchConfig        `mapstructure:"search"`
+	Webhook       WebhookConfig       `mapstructure:"webhook"`
 }
 
 // ServerConfig 服务器配置
@@ -23,10 +31,25 @@ type ServerConfig struct {
 
 // JWTConfig JWT配置
 type JWTConfig struct {
-	SecretKey            string `mapstructure:"secret_key"`
-	Issuer               string `mapstructure:"issuer"`
-	AccessTokenDuration  int    `mapstructure:"access_token_duration"`  // 秒
-	RefreshTokenDuration int    `mapstructure:"refresh_token_duration"` // 秒
+	SecretKey            string         `mapstructure:"secret_key"`
+	Issuer               string         `mapstructure:"issuer"`
+	AccessTokenDuration  int            `mapstructure:"access_token_duration"`  // 秒
+	RefreshTokenDuration int            `mapstructure:"refresh_token_duration"` // 秒
+	MaxSessionsPerUser   int            `mapstructure:"max_sessions_per_user"`  // 每个用户最大会话数，0 表示不限制
+	ImpersonationTTL     int            `mapstructure:"impersonation_ttl"`      // 管理员代登录令牌有效期（秒）
+	Algorithm            string         `mapstructure:"algorithm"`              // 签名算法：HS256（默认）、RS256、ES256、EdDSA
+	SigningKeyID         string         `mapstructure:"signing_key_id"`         // 当前签名密钥ID（非对称算法时必填）
+	Keys                 []JWTKeyConfig `mapstructure:"keys"`                   // 非对称密钥列表
+}
+
+// JWTKeyConfig JWT 非对称密钥配置
+// 轮换密钥时新增签名密钥并切换 signing_key_id，旧密钥保留到 expires_at，期间其签发的令牌仍可验证
+type JWTKeyConfig struct {
+	ID             string `mapstructure:"kid"`              // 密钥ID
+	Algorithm      string `mapstructure:"algorithm"`        // 签名算法，为空时使用 jwt.algorithm
+	PrivateKeyFile string `mapstructure:"private_key_file"` // PEM 私钥文件，仅用于验证的旧密钥可不配置
+	PublicKeyFile  string `mapstructure:"public_key_file"`  // PEM 公钥文件，为空时从私钥推导
+	ExpiresAt      string `mapstructure:"expires_at"`       // 停止验证的时间（RFC3339），为空表示长期有效
 }
 
 // DatabaseConfig 数据库配置
@@ -60,10 +83,75 @@ type SnowflakeConfig struct {
 
 // BusinessConfig 业务配置
 type BusinessConfig struct {
-	DefaultPageSize   int `mapstructure:"default_page_size"`
-	MaxPageSize       int `mapstructure:"max_page_size"`
-	PasswordMinLength int `mapstructure:"password_min_length"`
-	UsernameMinLength int `mapstructure:"username_min_length"`
+	DefaultPageSize      int `mapstructure:"default_page_size"`
+	MaxPageSize          int `mapstructure:"max_page_size"`
+	PasswordMinLength    int `mapstructure:"password_min_length"`
+	UsernameMinLength    int `mapstructure:"username_min_length"`
+	LoginMaxFailures     int `mapstructure:"login_max_failures"`     // 同一用户名允许的连续登录失败次数，0 表示不限制
+	LoginIPMaxFailures   int `mapstructure:"login_ip_max_failures"`  // 同一IP允许的登录失败次数，0 表示不限制
+	LoginFailureWindow   int `mapstructure:"login_failure_window"`   // 失败次数统计窗口（秒）
+	LoginLockoutDuration int `mapstructure:"login_lockout_duration"` // 达到上限后的锁定时长（秒）
+}
+
+// PasswordConfig 密码策略配置，最小长度使用 business.password_min_length
+type PasswordConfig struct {
+	MaxLength      int    `mapstructure:"max_length"`       // 最大长度，不超过 72
+	MinCharClasses int    `mapstructure:"min_char_classes"` // 至少包含的字符种类数（大写、小写、数字、符号），0 表示不限制
+	CheckUserInfo  bool   `mapstructure:"check_user_info"`  // 是否禁止与用户名、邮箱相似
+	BlocklistFile  string `mapstructure:"blocklist_file"`   // 常见/已泄露密码列表文件，为空表示不检查
+}
+
+// HomeConfig 首页内容配置
+type HomeConfig struct {
+	TrashRetentionDays   int `mapstructure:"trash_retention_days"`   // 回收站保留天数，超过后彻底删除，0 表示不自动清理
+	TrashPurgeInterval   int `mapstructure:"trash_purge_interval"`   // 回收站清理任务执行间隔（秒）
+	PublishCheckInterval int `mapstructure:"publish_check_interval"` // 定时上下线任务重新计算下一次上下线时间的最长间隔（秒）
+	ImportMaxRows        int `mapstructure:"import_max_rows"`        // 单次导入的最大行数，0 表示不限制
+	ImportMaxFileSize    int `mapstructure:"import_max_file_size"`   // 导入文件大小上限（MB）
+	CacheTTL             int `mapstructure:"cache_ttl"`              // 首页列表和详情的缓存时长（秒），0 表示不缓存
+	StatsFlushInterval   int `mapstructure:"stats_flush_interval"`   // 曝光点击计数从 Redis 写入数据库的间隔（秒），0 表示不写入
+}
+
+// RBACConfig 角色权限配置
+type RBACConfig struct {
+	AdminUsernames []string `mapstructure:"admin_usernames"` // 启动时自动授予管理员角色的用户名
+}
+
+// TwoFactorConfig 两步验证配置
+type TwoFactorConfig struct {
+	Issuer        string `mapstructure:"issuer"`         // 认证器中显示的签发方名称
+	EncryptionKey string `mapstructure:"encryption_key"` // TOTP 密钥的加密密钥
+	ChallengeTTL  int    `mapstructure:"challenge_ttl"`  // 登录挑战令牌有效期（秒）
+}
+
+// MailConfig 邮件配置
+type MailConfig struct {
+	Driver         string `mapstructure:"driver"`           // 邮件驱动：smtp、file
+	From           string `mapstructure:"from"`             // 发件人地址
+	SMTPHost       string `mapstructure:"smtp_host"`        // SMTP 服务器地址
+	SMTPPort       int    `mapstructure:"smtp_port"`        // SMTP 端口
+	SMTPUsername   string `mapstructure:"smtp_username"`    // SMTP 用户名
+	SMTPPassword   string `mapstructure:"smtp_password"`    // SMTP 密码
+	FilePath       string `mapstructure:"file_path"`        // file 驱动的输出文件，为空时输出到日志
+	LinkBaseURL    string `mapstructure:"link_base_url"`    // 邮件中重置密码、验证邮箱链接的前端地址
+	ResetTokenTTL  int    `mapstructure:"reset_token_ttl"`  // 重置密码令牌有效期（秒）
+	VerifyTokenTTL int    `mapstructure:"verify_token_ttl"` // 邮箱验证令牌有效期（秒）
+}
+
+// OAuthConfig 第三方登录（OAuth2 / OIDC）配置
+type OAuthConfig struct {
+	CallbackBaseURL string                         `mapstructure:"callback_base_url"` // 回调地址前缀，回调地址为 {callback_base_url}/api/user/oauth/{provider}/callback
+	StateTTL        int                            `mapstructure:"state_ttl"`         // 授权请求（state）有效期（秒）
+	Providers       map[string]OAuthProviderConfig `mapstructure:"providers"`         // 身份提供方，键为 provider 名称
+}
+
+// OAuthProviderConfig 身份提供方配置（通过 OIDC 发现文档获取端点）
+type OAuthProviderConfig struct {
+	Issuer       string   `mapstructure:"issuer"`        // 签发方地址
+	ClientID     string   `mapstructure:"client_id"`     // 客户端ID
+	ClientSecret string   `mapstructure:"client_secret"` // 客户端密钥
+	Scopes       []string `mapstructure:"scopes"`        // 申请的权限范围，默认 openid email profile
+	AutoCreate   bool     `mapstructure:"auto_create"`   // 首次登录时是否自动创建用户
 }
 
 // LogConfig 日志配置
@@ -86,6 +174,27 @@ type ElasticsearchConfig struct {
 	MaxRetry int      `mapstructure:"max_retry"` // 最大重试次数
 }
 
+// SearchConfig 业务数据全文检索配置（Elasticsearch）
+type SearchConfig struct {
+	Enabled   bool     `mapstructure:"enabled"`    // 是否启用，未启用或 ES 不可用时使用 MySQL 模糊搜索
+	Addrs     []string `mapstructure:"addrs"`      // ES 集群地址，为空时使用 elasticsearch.addrs
+	Username  string   `mapstructure:"username"`   // 用户名（可选），addrs 为空时使用 elasticsearch.username
+	Password  string   `mapstructure:"password"`   // 密码（可选），addrs 为空时使用 elasticsearch.password
+	HomeIndex string   `mapstructure:"home_index"` // 首页内容索引别名
+	Analyzer  string   `mapstructure:"analyzer"`   // 分词器：ngram（默认）或 ik（需安装 IK 分词插件）
+	Timeout   int      `mapstructure:"timeout"`    // 查询超时（秒），超时后回退到 MySQL
+}
+
+// WebhookConfig 出站 webhook 投递配置
+type WebhookConfig struct {
+	Workers         int `mapstructure:"workers"`          // 同时投递的最大请求数，0 表示不启动投递任务
+	PollInterval    int `mapstructure:"poll_interval"`    // 队列为空时的轮询间隔（秒）
+	DeliveryTimeout int `mapstructure:"delivery_timeout"` // 单次投递的请求超时（秒）
+	MaxAttempts     int `mapstructure:"max_attempts"`     // 最大投递次数（含首次），超过后放弃
+	RetryBaseDelay  int `mapstructure:"retry_base_delay"` // 首次重试的基础间隔（秒），之后每次翻倍
+	RetryMaxDelay   int `mapstructure:"retry_max_delay"`  // 重试间隔上限（秒）
+}
+
 // LogstashConfig Logstash配置
 type LogstashConfig struct {
 	Enabled    bool   `mapstructure:"enabled"`     // 是否启用 Logstash
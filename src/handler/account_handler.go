@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"errors"
+	"hi-go/src/model"
+	"hi-go/src/service"
+	"hi-go/src/utils/passwordpolicy"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccountHandler 账号安全处理器（找回密码、邮箱验证、个人资料、注销账号）
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+// NewAccountHandler 创建账号安全处理器实例
+func NewAccountHandler() *AccountHandler {
+	return &AccountHandler{
+		accountService: service.NewAccountService(),
+	}
+}
+
+// ForgotPassword 忘记密码
+// @Summary      忘记密码
+// @Description  向注册邮箱发送重置密码链接。无论邮箱是否已注册都返回成功
+// @Tags         账号安全
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.ForgotPasswordRequest  true  "注册邮箱"
+// @Success      200      {object}  model.Response  "发送成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /user/password/forgot [post]
+func (h *AccountHandler) ForgotPassword(c *gin.Context) {
+	var req model.ForgotPasswordRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层发送重置邮件
+	if err := h.accountService.ForgotPassword(req.Email); err != nil {
+		model.ServerError(c, "发送失败: "+err.Error())
+		return
+	}
+
+	model.SuccessWithMessage(c, "如果该邮箱已注册，您将收到重置密码邮件", nil)
+}
+
+// ResetPassword 重置密码
+// @Summary      通过邮件链接重置密码
+// @Description  使用重置密码邮件中的令牌设置新密码，令牌只能使用一次，重置后该用户的全部会话失效
+// @Tags         账号安全
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.ResetPasswordByTokenRequest  true  "重置令牌和新密码"
+// @Success      200      {object}  model.Response  "重置成功"
+// @Failure      400      {object}  model.Response{data=model.FieldErrorsData}  "参数错误、令牌无效或密码不符合策略"
+// @Router       /user/password/reset [post]
+func (h *AccountHandler) ResetPassword(c *gin.Context) {
+	var req model.ResetPasswordByTokenRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层重置密码
+	if err := h.accountService.ResetPassword(c, req.Token, req.Password); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "重置成功，请使用新密码登录", nil)
+}
+
+// VerifyEmail 验证邮箱
+// @Summary      验证邮箱
+// @Description  使用验证邮件中的令牌完成邮箱验证，令牌只能使用一次
+// @Tags         账号安全
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.VerifyEmailRequest  true  "验证令牌"
+// @Success      200      {object}  model.Response  "验证成功"
+// @Failure      400      {object}  model.Response  "参数错误或令牌无效"
+// @Router       /user/email/verify [post]
+func (h *AccountHandler) VerifyEmail(c *gin.Context) {
+	var req model.VerifyEmailRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层验证邮箱
+	if err := h.accountService.VerifyEmail(req.Token); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "验证成功", nil)
+}
+
+// SendVerificationEmail 发送邮箱验证邮件
+// @Summary      发送邮箱验证邮件
+// @Description  向当前用户的邮箱重新发送验证邮件
+// @Tags         账号安全
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  model.Response  "发送成功"
+// @Failure      400  {object}  model.Response  "未设置邮箱、邮箱已验证或发送过于频繁"
+// @Failure      401  {object}  model.Response  "未授权"
+// @Router       /user/email/send-verification [post]
+func (h *AccountHandler) SendVerificationEmail(c *gin.Context) {
+	if err := h.accountService.SendVerificationEmail(getUserID(c)); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "发送成功", nil)
+}
+
+// UpdateProfile 修改个人资料
+// @Summary      修改个人资料
+// @Description  修改当前用户的昵称、手机号、邮箱和头像，未传的字段保持不变。邮箱不能清空，修改邮箱后需要重新验证，系统会向新邮箱发送验证邮件
+// @Tags         账号安全
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      model.UpdateProfileRequest  true  "个人资料"
+// @Success      200      {object}  model.Response{data=model.User}  "修改成功"
+// @Failure      400      {object}  model.Response  "参数错误、邮箱为空或邮箱已存在"
+// @Failure      401      {object}  model.Response  "未授权"
+// @Router       /user/profile [put]
+func (h *AccountHandler) UpdateProfile(c *gin.Context) {
+	var req model.UpdateProfileRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层修改资料
+	user, err := h.accountService.UpdateProfile(getUserID(c), &req)
+	if err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "修改成功", user)
+}
+
+// ChangePassword 修改密码
+// @Summary      修改密码
+// @Description  校验原密码后设置新密码，当前会话保持登录，其他设备上的会话全部失效
+// @Tags         账号安全
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      model.ChangePasswordRequest  true  "原密码和新密码"
+// @Success      200      {object}  model.Response  "修改成功"
+// @Failure      400      {object}  model.Response{data=model.FieldErrorsData}  "参数错误、原密码错误或新密码不符合策略"
+// @Failure      401      {object}  model.Response  "未授权"
+// @Router       /user/password/change [post]
+func (h *AccountHandler) ChangePassword(c *gin.Context) {
+	var req model.ChangePasswordRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层修改密码
+	if err := h.accountService.ChangePassword(c, getUserID(c), c.GetString("sessionID"), &req); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "修改成功", nil)
+}
+
+// DeleteAccount 注销账号
+// @Summary      注销账号
+// @Description  校验密码（已开启两步验证时还需验证码）后注销当前账号：个人信息被匿名化，第三方登录绑定、API Key 和全部会话随之删除，操作不可撤销
+// @Tags         账号安全
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      model.DeleteAccountRequest  true  "当前密码和两步验证码"
+// @Success      200      {object}  model.Response  "注销成功"
+// @Failure      400      {object}  model.Response  "参数错误、密码错误或验证码错误"
+// @Failure      401      {object}  model.Response  "未授权"
+// @Router       /user/account [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	var req model.DeleteAccountRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层注销账号
+	if err := h.accountService.DeleteAccount(c, getUserID(c), &req); err != nil {
+		respondAccountError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "注销成功", nil)
+}
+
+// respondAccountError 根据账号安全服务返回的错误输出响应
+func respondAccountError(c *gin.Context, err error) {
+	if respondPasswordPolicyError(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		model.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrInvalidResetToken),
+		errors.Is(err, service.ErrInvalidVerifyToken),
+		errors.Is(err, service.ErrUserDisabled),
+		errors.Is(err, service.ErrEmailNotSet),
+		errors.Is(err, service.ErrEmailVerified),
+		errors.Is(err, service.ErrMailTooFrequent),
+		errors.Is(err, service.ErrInvalidPhone),
+		errors.Is(err, service.ErrEmailRequired),
+		errors.Is(err, service.ErrEmailExists),
+		errors.Is(err, service.ErrPasswordIncorrect),
+		errors.Is(err, service.ErrPasswordUnchanged),
+		errors.Is(err, service.ErrTwoFactorInvalidCode):
+		model.ParamError(c, err.Error())
+	default:
+		model.ServerError(c, "操作失败: "+err.Error())
+	}
+}
+
+// respondPasswordPolicyError 密码不符合策略时输出逐项的失败详情，返回是否已处理
+func respondPasswordPolicyError(c *gin.Context, err error) bool {
+	var policyErr *passwordpolicy.Error
+	if !errors.As(err, &policyErr) {
+		return false
+	}
+
+	fieldErrors := make([]model.FieldError, 0, len(policyErr.Violations))
+	for _, v := range policyErr.Violations {
+		fieldErrors = append(fieldErrors, model.FieldError{Field: v.Field, Code: v.Code, Message: v.Message})
+	}
+	model.FieldErrors(c, policyErr.Error(), fieldErrors)
+	return true
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"errors"
+	"hi-go/src/model"
+	"hi-go/src/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHandler API Key 处理器
+type APIKeyHandler struct {
+	apiKeyService *service.APIKeyService
+}
+
+// NewAPIKeyHandler 创建 API Key 处理器实例
+func NewAPIKeyHandler() *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: service.NewAPIKeyService(),
+	}
+}
+
+// Create 创建 API Key
+// @Summary      创建 API Key
+// @Description  创建个人 API Key，供脚本、CI 等通过 "Authorization: ApiKey {key}" 调用接口。Key 明文只返回一次。可用作用域：webhook、home:read、home:write
+// @Tags         API Key
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      model.APIKeyCreateRequest  true  "创建请求参数"
+// @Success      200      {object}  model.Response{data=model.APIKeyCreateResponse}  "创建成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      401      {object}  model.Response  "未授权"
+// @Router       /user/api-keys [post]
+func (h *APIKeyHandler) Create(c *gin.Context) {
+	var req model.APIKeyCreateRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层创建
+	resp, err := h.apiKeyService.Create(c, getUserID(c), &req)
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "创建成功", resp)
+}
+
+// List 获取 API Key 列表
+// @Summary      获取 API Key 列表
+// @Description  获取当前用户的全部 API Key（不含明文）
+// @Tags         API Key
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  model.Response{data=[]model.APIKey}  "获取成功"
+// @Failure      401  {object}  model.Response  "未授权"
+// @Router       /user/api-keys [get]
+func (h *APIKeyHandler) List(c *gin.Context) {
+	keys, err := h.apiKeyService.List(getUserID(c))
+	if err != nil {
+		model.ServerError(c, "获取列表失败: "+err.Error())
+		return
+	}
+
+	model.Success(c, keys)
+}
+
+// Revoke 吊销 API Key
+// @Summary      吊销 API Key
+// @Description  删除当前用户的指定 API Key，使用该 Key 的请求随即失败
+// @Tags         API Key
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int64  true  "API Key ID"
+// @Success      200  {object}  model.Response  "吊销成功"
+// @Failure      400  {object}  model.Response  "参数错误"
+// @Failure      404  {object}  model.Response  "API Key 不存在"
+// @Router       /user/api-keys/{id} [delete]
+func (h *APIKeyHandler) Revoke(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的 API Key ID")
+		return
+	}
+
+	// 2. 调用服务层吊销
+	if err := h.apiKeyService.Revoke(c, getUserID(c), id); err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "吊销成功", nil)
+}
+
+// respondAPIKeyError 根据 API Key 服务返回的错误输出响应
+func respondAPIKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		model.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrAPIKeyLimit),
+		errors.Is(err, service.ErrAPIKeyExpiresAtOld):
+		model.ParamError(c, err.Error())
+	default:
+		model.ServerError(c, "操作失败: "+err.Error())
+	}
+}
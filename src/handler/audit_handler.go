@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/service/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler 审计日志处理器（管理员）
+type AuditHandler struct{}
+
+// NewAuditHandler 创建审计日志处理器实例
+func NewAuditHandler() *AuditHandler {
+	return &AuditHandler{}
+}
+
+// List 查询审计日志
+// @Summary      查询审计日志
+// @Description  分页查询登录和管理操作的审计日志，按时间倒序。action 以 . 结尾时按前缀匹配，如 auth. 查询全部认证操作
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        actor_id    query     int64   false  "操作人用户ID"
+// @Param        action      query     string  false  "操作类型，如 auth.login、webhook."
+// @Param        start_time  query     string  false  "开始时间（含），格式 2006-01-02 15:04:05"
+// @Param        end_time    query     string  false  "结束时间（不含），格式 2006-01-02 15:04:05"
+// @Param        page        query     int     false  "页码（默认1）"
+// @Param        page_size   query     int     false  "每页数量（默认20，最大100）"
+// @Success      200         {object}  model.Response{data=model.AuditLogListDataResponse}  "获取成功"
+// @Failure      400         {object}  model.Response  "参数错误"
+// @Failure      403         {object}  model.Response  "无权限"
+// @Router       /user/admin/audit-logs [get]
+func (h *AuditHandler) List(c *gin.Context) {
+	var req model.AuditLogListRequest
+
+	// 1. 绑定查询参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+	if req.StartTime != nil && req.EndTime != nil && !req.StartTime.Before(*req.EndTime) {
+		model.ParamError(c, "开始时间必须早于结束时间")
+		return
+	}
+
+	// 2. 查询审计日志
+	resp, err := audit.List(&req)
+	if err != nil {
+		model.ServerError(c, "获取列表失败: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	model.Success(c, resp)
+}
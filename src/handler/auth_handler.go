@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"hi-go/src/model"
 	"hi-go/src/service"
 	"strconv"
@@ -30,6 +31,7 @@ func NewAuthHandler() *AuthHandler {
 // @Success      200      {object}  model.Response{data=model.LoginDataResponse}  "登录成功"
 // @Failure      400      {object}  model.Response  "参数错误"
 // @Failure      401      {object}  model.Response  "认证失败"
+// @Failure      429      {object}  model.Response{data=model.RetryAfterData}  "失败次数过多，已被临时锁定"
 // @Router       /user/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req model.LoginRequest
@@ -41,8 +43,81 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// 2. 调用服务层登录
-	resp, err := h.authService.Login(&req)
+	resp, err := h.authService.Login(c, &req, getClientInfo(c))
 	if err != nil {
+		var lockedErr *service.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			model.TooManyTries(c, err.Error(), int64(lockedErr.RetryAfter.Seconds()))
+			return
+		}
+		model.Unauthorized(c, err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	model.Success(c, resp)
+}
+
+// Refresh 刷新令牌接口
+// @Summary      刷新令牌
+// @Description  使用刷新令牌换取新的访问令牌和刷新令牌，旧的刷新令牌随即失效
+// @Tags         认证模块
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.RefreshTokenRequest  true  "刷新令牌请求参数"
+// @Success      200      {object}  model.Response{data=model.LoginDataResponse}  "刷新成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      401      {object}  model.Response  "刷新令牌无效或已失效"
+// @Router       /user/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req model.RefreshTokenRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层刷新令牌
+	resp, err := h.authService.RefreshToken(c, &req)
+	if err != nil {
+		model.Unauthorized(c, err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	model.Success(c, resp)
+}
+
+// LoginTwoFactor 登录二次验证接口
+// @Summary      登录二次验证
+// @Description  已开启两步验证的用户登录时，使用登录接口返回的挑战令牌和认证器验证码（或恢复码）完成登录
+// @Tags         认证模块
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.LoginTwoFactorRequest  true  "二次验证请求参数"
+// @Success      200      {object}  model.Response{data=model.LoginDataResponse}  "登录成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      401      {object}  model.Response  "验证码错误或挑战已失效"
+// @Failure      429      {object}  model.Response{data=model.RetryAfterData}  "失败次数过多，已被临时锁定"
+// @Router       /user/login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req model.LoginTwoFactorRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层完成二次验证
+	resp, err := h.authService.LoginTwoFactor(c, &req, getClientInfo(c))
+	if err != nil {
+		var lockedErr *service.LoginLockedError
+		if errors.As(err, &lockedErr) {
+			model.TooManyTries(c, err.Error(), int64(lockedErr.RetryAfter.Seconds()))
+			return
+		}
 		model.Unauthorized(c, err.Error())
 		return
 	}
@@ -59,7 +134,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Produce      json
 // @Param        request  body      model.RegisterRequest  true  "注册请求参数"
 // @Success      200      {object}  model.Response{data=model.User}  "注册成功"
-// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      400      {object}  model.Response{data=model.FieldErrorsData}  "参数错误或密码不符合策略"
 // @Router       /user/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req model.RegisterRequest
@@ -71,9 +146,11 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	}
 
 	// 2. 调用服务层注册
-	user, err := h.authService.Register(&req)
+	user, err := h.authService.Register(c, &req)
 	if err != nil {
-		model.ParamError(c, err.Error())
+		if !respondPasswordPolicyError(c, err) {
+			model.ParamError(c, err.Error())
+		}
 		return
 	}
 
@@ -122,3 +199,171 @@ func (h *AuthHandler) GetProfile(c *gin.Context) {
 
 	model.Success(c, user)
 }
+
+// Logout 退出登录
+// @Summary      退出登录
+// @Description  吊销当前会话的访问令牌和刷新令牌，其他设备上的会话不受影响
+// @Tags         认证模块
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  model.Response  "退出成功"
+// @Failure      401  {object}  model.Response  "未授权"
+// @Failure      500  {object}  model.Response  "服务器错误"
+// @Router       /user/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	// 1. 获取用户 ID
+	userID := getUserID(c)
+
+	// 2. 调用服务层吊销当前会话
+	if err := h.authService.Logout(c, userID, c.GetString("sessionID")); err != nil {
+		model.ServerError(c, "退出失败: "+err.Error())
+		return
+	}
+
+	model.SuccessWithMessage(c, "退出成功", nil)
+}
+
+// ListSessions 获取登录会话列表
+// @Summary      获取登录会话列表
+// @Description  获取当前用户在各个设备上的登录会话，current 标记当前请求所在的会话
+// @Tags         认证模块
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  model.Response{data=[]model.Session}  "获取成功"
+// @Failure      401  {object}  model.Response  "未授权"
+// @Failure      500  {object}  model.Response  "服务器错误"
+// @Router       /user/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	// 1. 获取用户 ID
+	userID := getUserID(c)
+
+	// 2. 调用服务层查询会话列表
+	sessions, err := h.authService.ListSessions(userID, c.GetString("sessionID"))
+	if err != nil {
+		model.ServerError(c, "获取会话列表失败: "+err.Error())
+		return
+	}
+
+	model.Success(c, sessions)
+}
+
+// DeleteSession 结束指定会话
+// @Summary      结束指定会话
+// @Description  结束当前用户的某个登录会话，该会话的令牌立即失效
+// @Tags         认证模块
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      string  true  "会话ID"
+// @Success      200  {object}  model.Response  "操作成功"
+// @Failure      401  {object}  model.Response  "未授权"
+// @Failure      404  {object}  model.Response  "会话不存在"
+// @Router       /user/sessions/{id} [delete]
+func (h *AuthHandler) DeleteSession(c *gin.Context) {
+	// 1. 获取用户 ID
+	userID := getUserID(c)
+
+	// 2. 调用服务层结束会话
+	if err := h.authService.DeleteSession(userID, c.Param("id")); err != nil {
+		if err == service.ErrSessionNotFound {
+			model.NotFound(c, err.Error())
+			return
+		}
+		model.ServerError(c, "操作失败: "+err.Error())
+		return
+	}
+
+	model.SuccessWithMessage(c, "操作成功", nil)
+}
+
+// RevokeSessions 吊销用户全部会话
+// @Summary      吊销用户全部会话
+// @Description  管理员强制指定用户下线，吊销其全部访问令牌和刷新令牌
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int64  true  "用户ID"
+// @Success      200  {object}  model.Response  "吊销成功"
+// @Failure      400  {object}  model.Response  "参数错误"
+// @Failure      403  {object}  model.Response  "无权限"
+// @Failure      404  {object}  model.Response  "用户不存在"
+// @Router       /user/admin/users/{id}/revoke-sessions [post]
+func (h *AuthHandler) RevokeSessions(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 调用服务层吊销会话
+	if err := h.authService.RevokeSessions(c, getUserID(c), id); err != nil {
+		if err == service.ErrUserNotFound {
+			model.NotFound(c, err.Error())
+			return
+		}
+		model.ServerError(c, "吊销失败: "+err.Error())
+		return
+	}
+
+	model.SuccessWithMessage(c, "吊销成功", nil)
+}
+
+// Impersonate 管理员代登录
+// @Summary      管理员代登录
+// @Description  为指定用户签发短期访问令牌（不含刷新令牌），用于排查用户看到的内容。令牌中的 act 字段记录实际操作的管理员，代登录期间的每个请求都写入审计日志，且不能修改密码、邮箱、两步验证、API Key 或注销账号。不能代登录管理员
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int64                     true  "用户ID"
+// @Param        request  body      model.ImpersonateRequest  true  "代登录原因"
+// @Success      200      {object}  model.Response{data=model.LoginDataResponse}  "签发成功"
+// @Failure      400      {object}  model.Response  "参数错误、用户已禁用或不能代登录该用户"
+// @Failure      403      {object}  model.Response  "无权限"
+// @Failure      404      {object}  model.Response  "用户不存在"
+// @Router       /user/admin/users/{id}/impersonate [post]
+func (h *AuthHandler) Impersonate(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 绑定并验证请求参数
+	var req model.ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用服务层签发代登录令牌
+	resp, err := h.authService.Impersonate(c, getUserID(c), id, req.Reason, getClientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUserNotFound):
+			model.NotFound(c, err.Error())
+		case errors.Is(err, service.ErrCannotModifySelf),
+			errors.Is(err, service.ErrUserDisabled),
+			errors.Is(err, service.ErrImpersonateAdmin):
+			model.ParamError(c, err.Error())
+		default:
+			model.ServerError(c, "代登录失败: "+err.Error())
+		}
+		return
+	}
+
+	model.Success(c, resp)
+}
+
+// getClientInfo 从请求中提取客户端信息
+func getClientInfo(c *gin.Context) *model.ClientInfo {
+	return &model.ClientInfo{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
@@ -1,12 +1,28 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"hi-go/src/config"
 	"hi-go/src/model"
 	"hi-go/src/service"
+	"hi-go/src/utils/logger"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
+// homeExportContentTypes 导出格式对应的 Content-Type
+var homeExportContentTypes = map[string]string{
+	service.HomeFormatCSV:  "text/csv; charset=utf-8",
+	service.HomeFormatJSON: "application/json; charset=utf-8",
+	service.HomeFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
 // HomeHandler 首页处理器
 type HomeHandler struct {
 	homeService *service.HomeService
@@ -21,14 +37,17 @@ func NewHomeHandler() *HomeHandler {
 
 // List 获取首页列表
 // @Summary      获取首页内容列表
-// @Description  分页获取首页内容列表，包括标题、描述、图片等信息
+// @Description  分页获取首页内容列表，包括标题、描述、图片等信息，只返回当前处于展示时间内的内容。指定版位时按版位的排序方式返回，且最多返回版位设置的条数；版位已禁用时列表为空
 // @Tags         首页模块
 // @Accept       json
 // @Produce      json
-// @Param        page       query     int  false  "页码（默认1）"
-// @Param        page_size  query     int  false  "每页数量（默认20，最大100）"
+// @Param        slot       query     string  false  "版位编码"
+// @Param        tag        query     string  false  "标签名称"
+// @Param        page       query     int     false  "页码（默认1）"
+// @Param        page_size  query     int     false  "每页数量（默认20，最大100）"
 // @Success      200        {object}  model.Response{data=model.HomeListDataResponse}  "获取成功"
 // @Failure      400        {object}  model.Response  "参数错误"
+// @Failure      404        {object}  model.Response  "版位不存在"
 // @Failure      500        {object}  model.Response  "服务器错误"
 // @Router       /home/list [get]
 func (h *HomeHandler) List(c *gin.Context) {
@@ -41,9 +60,43 @@ func (h *HomeHandler) List(c *gin.Context) {
 	}
 
 	// 2. 调用服务层获取列表
-	resp, err := h.homeService.GetList(&req)
+	resp, err := h.homeService.GetList(c.Request.Context(), &req)
+	if err != nil {
+		respondHomeSlotError(c, "获取列表失败: ", err)
+		return
+	}
+
+	// 3. 返回成功响应
+	model.Success(c, resp)
+}
+
+// Preview 预览首页列表
+// @Summary      预览首页内容列表
+// @Description  编辑人员预览首页列表：不传 at 时包含尚未到开始展示时间的内容；传 at 时返回该时间点用户将看到的列表
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        at         query     string  false  "预览时间点，格式 2006-01-02 15:04:05"
+// @Param        page       query     int     false  "页码（默认1）"
+// @Param        page_size  query     int     false  "每页数量（默认20，最大100）"
+// @Success      200        {object}  model.Response{data=model.HomeListDataResponse}  "获取成功"
+// @Failure      400        {object}  model.Response  "参数错误"
+// @Failure      403        {object}  model.Response  "无权限"
+// @Failure      500        {object}  model.Response  "服务器错误"
+// @Router       /home/preview [get]
+func (h *HomeHandler) Preview(c *gin.Context) {
+	var req model.HomePreviewRequest
+
+	// 1. 绑定查询参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层获取预览列表
+	resp, err := h.homeService.Preview(&req)
 	if err != nil {
-		model.ServerError(c, "获取列表失败: "+err.Error())
+		model.ServerError(c, "获取预览列表失败: "+err.Error())
 		return
 	}
 
@@ -62,7 +115,7 @@ func (h *HomeHandler) List(c *gin.Context) {
 // @Router       /home/create [post]
 func (h *HomeHandler) Create(c *gin.Context) {
 	// 调用服务层创建30条模拟数据
-	if err := h.homeService.CreateMockData(); err != nil {
+	if err := h.homeService.CreateMockData(c, getUserID(c)); err != nil {
 		model.ServerError(c, "创建数据失败: "+err.Error())
 		return
 	}
@@ -72,7 +125,7 @@ func (h *HomeHandler) Create(c *gin.Context) {
 
 // Update 更新首页内容
 // @Summary      更新首页内容
-// @Description  根据ID更新首页内容信息
+// @Description  根据ID更新首页内容信息，可设置开始、结束展示时间实现定时上下线，以及所属版位和标签
 // @Tags         首页模块
 // @Accept       json
 // @Produce      json
@@ -92,7 +145,11 @@ func (h *HomeHandler) Update(c *gin.Context) {
 	}
 
 	// 3. 调用服务层更新
-	if err := h.homeService.Update(&req); err != nil {
+	if err := h.homeService.Update(c, &req, getUserID(c)); err != nil {
+		if errors.Is(err, service.ErrHomeInvalidWindow) || errors.Is(err, service.ErrHomeSlotNotFound) {
+			model.ParamError(c, err.Error())
+			return
+		}
 		model.ServerError(c, "更新失败: "+err.Error())
 		return
 	}
@@ -102,7 +159,7 @@ func (h *HomeHandler) Update(c *gin.Context) {
 
 // Delete 删除首页内容
 // @Summary      删除首页内容
-// @Description  根据ID删除首页内容信息
+// @Description  根据ID删除首页内容，删除后移入回收站，保留期内可恢复
 // @Tags         首页模块
 // @Accept       json
 // @Produce      json
@@ -121,7 +178,7 @@ func (h *HomeHandler) Delete(c *gin.Context) {
 	}
 
 	// 2. 调用服务层删除
-	if err := h.homeService.Delete(&req); err != nil {
+	if err := h.homeService.Delete(c, &req, getUserID(c)); err != nil {
 		model.ServerError(c, "删除失败: "+err.Error())
 		return
 	}
@@ -129,17 +186,282 @@ func (h *HomeHandler) Delete(c *gin.Context) {
 	model.SuccessWithMessage(c, "删除成功", nil)
 }
 
+// Trash 获取回收站列表
+// @Summary      获取首页内容回收站
+// @Description  分页获取已删除的首页内容，按删除时间倒序，purge_at 为预计彻底删除时间
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        slot       query     string  false  "版位编码"
+// @Param        tag        query     string  false  "标签名称"
+// @Param        page       query     int     false  "页码（默认1）"
+// @Param        page_size  query     int     false  "每页数量（默认20，最大100）"
+// @Success      200        {object}  model.Response{data=model.HomeTrashListDataResponse}  "获取成功"
+// @Failure      400        {object}  model.Response  "参数错误"
+// @Failure      404        {object}  model.Response  "版位不存在"
+// @Failure      500        {object}  model.Response  "服务器错误"
+// @Router       /home/trash [get]
+func (h *HomeHandler) Trash(c *gin.Context) {
+	var req model.HomeListRequest
+
+	// 1. 绑定查询参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层获取回收站列表
+	resp, err := h.homeService.Trash(&req)
+	if err != nil {
+		respondHomeSlotError(c, "获取回收站失败: ", err)
+		return
+	}
+
+	// 3. 返回成功响应
+	model.Success(c, resp)
+}
+
+// Restore 从回收站恢复首页内容
+// @Summary      恢复首页内容
+// @Description  将回收站中的首页内容恢复到列表中
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.HomeRestoreRequest  true  "恢复请求参数"
+// @Success      200      {object}  model.Response  "恢复成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      404      {object}  model.Response  "回收站中不存在该内容"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /home/restore [post]
+func (h *HomeHandler) Restore(c *gin.Context) {
+	// 1. 绑定请求参数
+	var req model.HomeRestoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层恢复
+	if err := h.homeService.Restore(c, &req, getUserID(c)); err != nil {
+		respondHomeTrashError(c, "恢复失败: ", err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "恢复成功", nil)
+}
+
+// Purge 彻底删除回收站中的首页内容
+// @Summary      彻底删除首页内容（管理员）
+// @Description  从回收站中彻底删除首页内容，删除后不可恢复
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.HomePurgeRequest  true  "彻底删除请求参数"
+// @Success      200      {object}  model.Response  "删除成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      403      {object}  model.Response  "无权限"
+// @Failure      404      {object}  model.Response  "回收站中不存在该内容"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /home/purge [delete]
+func (h *HomeHandler) Purge(c *gin.Context) {
+	// 1. 绑定请求参数
+	var req model.HomePurgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层彻底删除
+	if err := h.homeService.Purge(c, &req, getUserID(c)); err != nil {
+		respondHomeTrashError(c, "删除失败: ", err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// respondHomeTrashError 根据回收站操作返回的错误输出响应
+func respondHomeTrashError(c *gin.Context, prefix string, err error) {
+	if errors.Is(err, service.ErrHomeNotInTrash) {
+		model.NotFound(c, err.Error())
+		return
+	}
+	model.ServerError(c, prefix+err.Error())
+}
+
+// respondHomeSlotError 按版位筛选时版位不存在返回 404，其他错误返回 500
+func respondHomeSlotError(c *gin.Context, prefix string, err error) {
+	if errors.Is(err, service.ErrHomeSlotNotFound) {
+		model.NotFound(c, err.Error())
+		return
+	}
+	model.ServerError(c, prefix+err.Error())
+}
+
+// Reorder 调整首页内容排序
+// @Summary      调整首页内容排序
+// @Description  按拖拽后的顺序提交内容ID及读取时的版本号，在同一事务中重写排序值。任一内容已被他人修改时不做任何修改，返回 409 及冲突内容的最新版本号
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.HomeReorderRequest  true  "排序请求参数"
+// @Success      200      {object}  model.Response{data=model.HomeReorderResult}  "调整成功，返回新的排序和版本号"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      404      {object}  model.Response  "记录不存在"
+// @Failure      409      {object}  model.Response{data=model.HomeReorderResult}  "内容已被他人修改"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /home/reorder [post]
+func (h *HomeHandler) Reorder(c *gin.Context) {
+	// 1. 绑定请求参数
+	var req model.HomeReorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层调整排序
+	result, err := h.homeService.Reorder(c, &req, getUserID(c))
+	switch {
+	case errors.Is(err, service.ErrHomeConflict):
+		model.Conflict(c, err.Error(), result)
+		return
+	case errors.Is(err, service.ErrHomeNotFound):
+		model.NotFound(c, err.Error())
+		return
+	case errors.Is(err, service.ErrHomeDuplicateID):
+		model.ParamError(c, err.Error())
+		return
+	case err != nil:
+		model.ServerError(c, "调整排序失败: "+err.Error())
+		return
+	}
+
+	model.SuccessWithMessage(c, "调整成功", result)
+}
+
+// Import 批量导入首页内容
+// @Summary      批量导入首页内容
+// @Description  上传 CSV、JSON 或 XLSX 文件批量创建首页内容。每行按更新接口的规则校验，任一行失败时不写入任何数据并返回逐行错误；dry_run=true 时只校验不写入
+// @Tags         首页模块
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file     formData  file    true   "导入文件，CSV/XLSX 第一行为表头：title,description,image_url,link,sort,status,publish_at,unpublish_at"
+// @Param        format   formData  string  false  "文件格式 csv/json/xlsx，为空时按扩展名判断"
+// @Param        dry_run  formData  bool    false  "只校验不写入"
+// @Success      200      {object}  model.Response{data=model.HomeImportResult}  "导入成功或校验通过"
+// @Failure      400      {object}  model.Response{data=model.HomeImportResult}  "参数错误或数据校验未通过"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /home/import [post]
+func (h *HomeHandler) Import(c *gin.Context) {
+	// 1. 绑定请求参数
+	var req model.HomeImportRequest
+	if err := c.ShouldBind(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		model.ParamError(c, "请上传导入文件")
+		return
+	}
+	if maxSize := int64(config.Config.Home.ImportMaxFileSize) << 20; maxSize > 0 && fileHeader.Size > maxSize {
+		model.ParamError(c, fmt.Sprintf("文件大小不能超过 %dMB", config.Config.Home.ImportMaxFileSize))
+		return
+	}
+	format := req.Format
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(fileHeader.Filename), "."))
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		model.ServerError(c, "读取文件失败: "+err.Error())
+		return
+	}
+	defer file.Close()
+
+	// 2. 调用服务层导入
+	result, err := h.homeService.Import(c, file, format, req.DryRun, getUserID(c))
+	switch {
+	case errors.Is(err, service.ErrHomeImportInvalid):
+		model.ErrorWithData(c, http.StatusBadRequest, model.CodeParamError, err.Error(), result)
+		return
+	case errors.Is(err, service.ErrHomeImportFormat),
+		errors.Is(err, service.ErrHomeImportParse),
+		errors.Is(err, service.ErrHomeImportEmpty),
+		errors.Is(err, service.ErrHomeImportTooLarge):
+		model.ParamError(c, err.Error())
+		return
+	case err != nil:
+		model.ServerError(c, "导入失败: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	if result.DryRun {
+		model.SuccessWithMessage(c, fmt.Sprintf("校验通过，共 %d 行", result.Total), result)
+		return
+	}
+	model.SuccessWithMessage(c, fmt.Sprintf("成功导入 %d 条", result.Imported), result)
+}
+
+// Export 导出首页内容
+// @Summary      导出首页内容
+// @Description  按关键词和状态筛选导出首页内容（不含回收站），数据边查询边输出
+// @Tags         首页模块
+// @Produce      text/csv
+// @Produce      json
+// @Produce      application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param        format   query     string  false  "导出格式 csv/json/xlsx（默认 csv）"
+// @Param        keyword  query     string  false  "关键词（标题或描述）"
+// @Param        status   query     int     false  "状态：1-启用 0-禁用，为空表示全部"
+// @Success      200      {file}    file    "导出文件"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /home/export [get]
+func (h *HomeHandler) Export(c *gin.Context) {
+	// 1. 绑定查询参数
+	var req model.HomeExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+	if req.Format == "" {
+		req.Format = service.HomeFormatCSV
+	}
+
+	// 2. 设置下载响应头，随后由服务层直接写入响应体
+	filename := fmt.Sprintf("home-%s.%s", time.Now().Format("20060102150405"), req.Format)
+	c.Header("Content-Type", homeExportContentTypes[req.Format])
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := h.homeService.Export(c, &req, getUserID(c), c.Writer); err != nil {
+		// 尚未输出数据时仍可返回错误响应，否则只能中断下载
+		if !c.Writer.Written() {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.Header().Del("Content-Disposition")
+			model.ServerError(c, "导出失败: "+err.Error())
+			return
+		}
+		logger.Error("导出首页内容中断", zap.String("format", req.Format), zap.Error(err))
+	}
+}
+
 // Search 搜索首页内容
 // @Summary      搜索首页内容
-// @Description  根据关键词搜索首页标题或描述
+// @Description  根据关键词搜索首页标题或描述。启用 Elasticsearch 时按相关度排序并返回高亮片段，ES 不可用时回退到 MySQL 模糊搜索，engine 字段标识实际使用的检索方式
 // @Tags         首页模块
 // @Accept       json
 // @Produce      json
 // @Param        keyword   query     string  false  "搜索关键词"
+// @Param        slot      query     string  false  "版位编码"
+// @Param        tag       query     string  false  "标签名称"
 // @Param        page      query     int     false  "页码（默认1）"
 // @Param        page_size query     int     false  "每页数量（默认20，最大100）"
-// @Success      200       {object}  model.Response{data=model.HomeListDataResponse}  "搜索成功"
+// @Success      200       {object}  model.Response{data=model.HomeSearchDataResponse}  "搜索成功"
 // @Failure      400       {object}  model.Response  "参数错误"
+// @Failure      404       {object}  model.Response  "版位不存在"
 // @Failure      500       {object}  model.Response  "服务器错误"
 // @Router       /home/search [get]
 func (h *HomeHandler) Search(c *gin.Context) {
@@ -152,9 +474,9 @@ func (h *HomeHandler) Search(c *gin.Context) {
 	}
 
 	// 2. 调用服务层搜索
-	resp, err := h.homeService.Search(&req)
+	resp, err := h.homeService.Search(c.Request.Context(), &req)
 	if err != nil {
-		model.ServerError(c, "搜索失败: "+err.Error())
+		respondHomeSlotError(c, "搜索失败: ", err)
 		return
 	}
 
@@ -164,7 +486,7 @@ func (h *HomeHandler) Search(c *gin.Context) {
 
 // GetByID 根据ID获取首页内容详情
 // @Summary      获取首页内容详情
-// @Description  根据ID获取首页内容的全部信息
+// @Description  根据ID获取首页内容的全部信息，只返回已启用且处于发布期内的内容（未上线的内容请使用预览接口）
 // @Tags         首页模块
 // @Accept       json
 // @Produce      json
@@ -184,7 +506,7 @@ func (h *HomeHandler) GetByID(c *gin.Context) {
 	}
 
 	// 2. 调用服务层查询
-	resp, err := h.homeService.GetByID(&req)
+	resp, err := h.homeService.GetByID(c.Request.Context(), &req)
 	if err != nil {
 		model.ParamError(c, err.Error())
 		return
@@ -193,3 +515,140 @@ func (h *HomeHandler) GetByID(c *gin.Context) {
 	// 3. 返回成功响应
 	model.Success(c, resp)
 }
+
+// Revisions 获取首页内容的修订记录
+// @Summary      获取首页内容修订记录
+// @Description  分页获取首页内容的修订记录（最新的在前），每条包含修改后的完整快照和修改人。第一次修改时会同时保存修改前的原始内容
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        id         path      int64  true   "首页内容ID"
+// @Param        page       query     int    false  "页码（默认1）"
+// @Param        page_size  query     int    false  "每页数量（默认20，最大100）"
+// @Success      200        {object}  model.Response{data=model.HomeRevisionListDataResponse}  "获取成功"
+// @Failure      400        {object}  model.Response  "参数错误"
+// @Failure      404        {object}  model.Response  "记录不存在"
+// @Failure      500        {object}  model.Response  "服务器错误"
+// @Router       /home/{id}/revisions [get]
+func (h *HomeHandler) Revisions(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的首页内容ID")
+		return
+	}
+
+	// 2. 绑定查询参数
+	var req model.HomeRevisionListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用服务层查询
+	resp, err := h.homeService.Revisions(id, &req)
+	if err != nil {
+		respondHomeRevisionError(c, "获取修订记录失败: ", err)
+		return
+	}
+
+	model.Success(c, resp)
+}
+
+// DiffRevisions 比较首页内容的两个修订记录
+// @Summary      比较首页内容修订记录
+// @Description  比较同一内容的两个修订记录，返回有变化的字段及其前后的值
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int64  true  "首页内容ID"
+// @Param        from  query     int64  true  "原修订记录ID"
+// @Param        to    query     int64  true  "新修订记录ID"
+// @Success      200   {object}  model.Response{data=model.HomeRevisionDiffResponse}  "比较成功"
+// @Failure      400   {object}  model.Response  "参数错误"
+// @Failure      404   {object}  model.Response  "修订记录不存在"
+// @Failure      500   {object}  model.Response  "服务器错误"
+// @Router       /home/{id}/revisions/diff [get]
+func (h *HomeHandler) DiffRevisions(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的首页内容ID")
+		return
+	}
+
+	// 2. 绑定查询参数
+	var req model.HomeRevisionDiffRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用服务层比较
+	resp, err := h.homeService.DiffRevisions(id, &req)
+	if err != nil {
+		respondHomeRevisionError(c, "比较失败: ", err)
+		return
+	}
+
+	model.Success(c, resp)
+}
+
+// Rollback 回滚首页内容到历史版本
+// @Summary      回滚首页内容
+// @Description  将内容恢复为指定修订记录的快照（含版位和标签），回滚本身会保存为一条新的修订记录
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        id       path      int64                      true  "首页内容ID"
+// @Param        request  body      model.HomeRollbackRequest  true  "回滚请求参数"
+// @Success      200      {object}  model.Response{data=model.Home}  "回滚成功，返回回滚后的内容"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      404      {object}  model.Response  "记录不存在"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /home/{id}/rollback [post]
+func (h *HomeHandler) Rollback(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的首页内容ID")
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req model.HomeRollbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用服务层回滚
+	home, err := h.homeService.Rollback(c, id, &req, getUserID(c))
+	if err != nil {
+		respondHomeRevisionError(c, "回滚失败: ", err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "回滚成功", home)
+}
+
+// respondHomeRevisionError 根据修订记录操作返回的错误输出响应
+func respondHomeRevisionError(c *gin.Context, prefix string, err error) {
+	if errors.Is(err, service.ErrHomeNotFound) || errors.Is(err, service.ErrHomeRevisionNotFound) {
+		model.NotFound(c, err.Error())
+		return
+	}
+	model.ServerError(c, prefix+err.Error())
+}
+
+// CacheStats 获取首页缓存统计
+// @Summary      获取首页缓存统计
+// @Description  获取当前实例自启动以来首页列表和详情缓存的命中、未命中及实际查询数据库的次数
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  model.Response{data=model.HomeCacheStatsResponse}  "获取成功"
+// @Router       /home/cache/stats [get]
+func (h *HomeHandler) CacheStats(c *gin.Context) {
+	model.Success(c, h.homeService.CacheStats())
+}
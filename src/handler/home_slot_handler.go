@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"hi-go/src/model"
+	"hi-go/src/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HomeSlotHandler 首页版位处理器
+type HomeSlotHandler struct {
+	slotService *service.HomeSlotService
+}
+
+// NewHomeSlotHandler 创建首页版位处理器实例
+func NewHomeSlotHandler() *HomeSlotHandler {
+	return &HomeSlotHandler{
+		slotService: service.NewHomeSlotService(),
+	}
+}
+
+// List 获取版位列表
+// @Summary      获取首页版位列表
+// @Description  获取全部版位（如轮播、宫格、弹窗）及其下的内容数量
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  model.Response{data=model.HomeSlotListDataResponse}  "获取成功"
+// @Failure      500  {object}  model.Response  "服务器错误"
+// @Router       /home/slot/list [get]
+func (h *HomeSlotHandler) List(c *gin.Context) {
+	resp, err := h.slotService.List()
+	if err != nil {
+		model.ServerError(c, "获取版位列表失败: "+err.Error())
+		return
+	}
+
+	model.Success(c, resp)
+}
+
+// GetByID 获取版位详情
+// @Summary      获取首页版位详情
+// @Description  根据ID获取版位信息
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        id   query     int64  true  "版位ID"
+// @Success      200  {object}  model.Response{data=model.HomeSlot}  "查询成功"
+// @Failure      400  {object}  model.Response  "参数错误"
+// @Failure      404  {object}  model.Response  "版位不存在"
+// @Failure      500  {object}  model.Response  "服务器错误"
+// @Router       /home/slot/detail [get]
+func (h *HomeSlotHandler) GetByID(c *gin.Context) {
+	var req model.HomeSlotGetByIDRequest
+
+	// 1. 绑定查询参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层查询
+	resp, err := h.slotService.GetByID(&req)
+	if err != nil {
+		respondHomeSlotError(c, "查询失败: ", err)
+		return
+	}
+
+	// 3. 返回成功响应
+	model.Success(c, resp)
+}
+
+// Create 创建版位
+// @Summary      创建首页版位
+// @Description  创建版位，设置内容排序方式（sort-按排序值，newest-最新在前）和最多展示的内容条数
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.HomeSlotCreateRequest  true  "创建请求参数"
+// @Success      200      {object}  model.Response{data=model.HomeSlot}  "创建成功"
+// @Failure      400      {object}  model.Response  "参数错误或编码已存在"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /home/slot/create [post]
+func (h *HomeSlotHandler) Create(c *gin.Context) {
+	// 1. 绑定请求参数
+	var req model.HomeSlotCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层创建
+	slot, err := h.slotService.Create(c, &req, getUserID(c))
+	if err != nil {
+		if errors.Is(err, service.ErrHomeSlotCodeExists) {
+			model.ParamError(c, err.Error())
+			return
+		}
+		model.ServerError(c, "创建失败: "+err.Error())
+		return
+	}
+
+	model.SuccessWithMessage(c, "创建成功", slot)
+}
+
+// Update 修改版位
+// @Summary      修改首页版位
+// @Description  根据ID修改版位名称、排序方式、展示条数和状态，编码不可修改
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.HomeSlotUpdateRequest  true  "修改请求参数"
+// @Success      200      {object}  model.Response  "修改成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      404      {object}  model.Response  "版位不存在"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /home/slot/update [post]
+func (h *HomeSlotHandler) Update(c *gin.Context) {
+	// 1. 绑定请求参数
+	var req model.HomeSlotUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层修改
+	if err := h.slotService.Update(c, &req, getUserID(c)); err != nil {
+		respondHomeSlotError(c, "修改失败: ", err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "修改成功", nil)
+}
+
+// Delete 删除版位
+// @Summary      删除首页版位
+// @Description  删除版位，版位下的内容保留并变为未分配版位
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.HomeSlotDeleteRequest  true  "删除请求参数"
+// @Success      200      {object}  model.Response  "删除成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      404      {object}  model.Response  "版位不存在"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /home/slot/delete [delete]
+func (h *HomeSlotHandler) Delete(c *gin.Context) {
+	// 1. 绑定请求参数
+	var req model.HomeSlotDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层删除
+	if err := h.slotService.Delete(c, &req, getUserID(c)); err != nil {
+		respondHomeSlotError(c, "删除失败: ", err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "删除成功", nil)
+}
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"hi-go/src/model"
+	"hi-go/src/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HomeStatsHandler 首页内容曝光点击统计处理器
+type HomeStatsHandler struct {
+	statsService *service.HomeStatsService
+}
+
+// NewHomeStatsHandler 创建统计处理器实例
+func NewHomeStatsHandler() *HomeStatsHandler {
+	return &HomeStatsHandler{
+		statsService: service.NewHomeStatsService(),
+	}
+}
+
+// Track 上报曝光/点击事件
+// @Summary      上报首页内容曝光/点击
+// @Description  客户端展示或点击首页内容时上报，一次最多 100 条。计数先记在 Redis，由定时任务汇总写入数据库
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        request  body      model.HomeTrackRequest  true  "事件列表"
+// @Success      200      {object}  model.Response  "上报成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      500      {object}  model.Response  "服务器错误"
+// @Router       /home/track [post]
+func (h *HomeStatsHandler) Track(c *gin.Context) {
+	var req model.HomeTrackRequest
+
+	// 1. 绑定请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层记录
+	if err := h.statsService.Track(c.Request.Context(), &req); err != nil {
+		model.ServerError(c, "上报失败: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	model.SuccessWithMessage(c, "上报成功", nil)
+}
+
+// Report 获取曝光点击报表
+// @Summary      获取首页内容曝光点击报表
+// @Description  按内容汇总日期范围内的曝光、点击次数和点击率，按曝光次数倒序。最近一个写入周期内的上报尚未计入
+// @Tags         首页模块
+// @Accept       json
+// @Produce      json
+// @Param        start_date  query     string  true   "开始日期（含），格式 2006-01-02"
+// @Param        end_date    query     string  true   "结束日期（含），跨度不超过366天"
+// @Param        id          query     int64   false  "只看指定内容"
+// @Param        page        query     int     false  "页码（默认1）"
+// @Param        page_size   query     int     false  "每页数量（默认20，最大100）"
+// @Success      200         {object}  model.Response{data=model.HomeStatsReportResponse}  "获取成功"
+// @Failure      400         {object}  model.Response  "参数错误"
+// @Failure      500         {object}  model.Response  "服务器错误"
+// @Router       /home/stats/report [get]
+func (h *HomeStatsHandler) Report(c *gin.Context) {
+	var req model.HomeStatsReportRequest
+
+	// 1. 绑定查询参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层查询
+	resp, err := h.statsService.Report(&req)
+	if err != nil {
+		if errors.Is(err, service.ErrHomeStatsDateRange) {
+			model.ParamError(c, err.Error())
+			return
+		}
+		model.ServerError(c, "获取报表失败: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	model.Success(c, resp)
+}
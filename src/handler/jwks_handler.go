@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"hi-go/src/utils/jwt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSHandler 公钥发布处理器
+type JWKSHandler struct{}
+
+// NewJWKSHandler 创建公钥发布处理器实例
+func NewJWKSHandler() *JWKSHandler {
+	return &JWKSHandler{}
+}
+
+// GetJWKS 获取令牌验证公钥
+// @Summary      获取令牌验证公钥（JWKS）
+// @Description  按 RFC 7517 返回当前可用于验证访问令牌的公钥集合，其他服务可据此按令牌头部的 kid 自行验证令牌。HS256 模式下返回空集合
+// @Tags         认证模块
+// @Produce      json
+// @Success      200  {object}  jwt.JWKSet  "公钥集合"
+// @Router       /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	// 标准 JWKS 格式，不使用统一响应包装，便于 JWT 库直接读取
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, jwt.JWKS())
+}
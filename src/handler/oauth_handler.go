@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/service"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OAuthHandler 第三方登录处理器
+type OAuthHandler struct {
+	oauthService *service.OAuthService
+}
+
+// NewOAuthHandler 创建第三方登录处理器实例
+func NewOAuthHandler() *OAuthHandler {
+	return &OAuthHandler{
+		oauthService: service.NewOAuthService(),
+	}
+}
+
+// Start 发起第三方登录
+// @Summary      发起第三方登录
+// @Description  生成 state、nonce 和 PKCE 校验码，写入与 state 绑定的 HttpOnly Cookie，并重定向到身份提供方的授权页面
+// @Tags         认证模块
+// @Param        provider  path      string  true   "身份提供方名称（配置中的键）"
+// @Param        device    query     string  false  "设备名称"
+// @Success      302       {string}  string  "重定向到授权页面"
+// @Failure      404       {object}  model.Response  "不支持的登录方式"
+// @Failure      500       {object}  model.Response  "服务器错误"
+// @Router       /user/oauth/{provider}/start [get]
+func (h *OAuthHandler) Start(c *gin.Context) {
+	device := c.Query("device")
+	if len(device) > 50 {
+		device = device[:50]
+	}
+
+	// 调用服务层生成授权地址
+	provider := c.Param("provider")
+	authURL, binding, err := h.oauthService.Start(provider, device)
+	if err != nil {
+		if errors.Is(err, service.ErrOAuthProviderNotFound) {
+			model.NotFound(c, err.Error())
+			return
+		}
+		model.ServerError(c, "发起登录失败: "+err.Error())
+		return
+	}
+
+	setOAuthStateCookie(c, provider, binding, int(config.GetOAuthStateTTL().Seconds()))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback 第三方登录回调
+// @Summary      第三方登录回调
+// @Description  身份提供方授权后回调，校验 state 及发起登录时写入的 Cookie 并验证 ID Token，按绑定关系或已验证邮箱找到用户（允许时自动创建），返回与密码登录相同的登录结果
+// @Tags         认证模块
+// @Produce      json
+// @Param        provider           path      string  true   "身份提供方名称"
+// @Param        code               query     string  false  "授权码"
+// @Param        state              query     string  false  "发起登录时生成的 state"
+// @Param        error              query     string  false  "错误码"
+// @Param        error_description  query     string  false  "错误描述"
+// @Success      200  {object}  model.Response{data=model.LoginDataResponse}  "登录成功"
+// @Failure      400  {object}  model.Response  "登录请求已失效"
+// @Failure      401  {object}  model.Response  "认证失败"
+// @Failure      404  {object}  model.Response  "不支持的登录方式"
+// @Router       /user/oauth/{provider}/callback [get]
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	var req model.OAuthCallbackRequest
+
+	// 1. 绑定查询参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 读取并清除发起登录时写入的 Cookie，state 只能使用一次
+	provider := c.Param("provider")
+	binding, _ := c.Cookie(service.OAuthStateCookie)
+	setOAuthStateCookie(c, provider, "", -1)
+
+	// 3. 调用服务层完成登录
+	resp, err := h.oauthService.Callback(c, provider, binding, &req, getClientInfo(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOAuthProviderNotFound):
+			model.NotFound(c, err.Error())
+		case errors.Is(err, service.ErrOAuthStateInvalid):
+			model.ParamError(c, err.Error())
+		default:
+			model.Unauthorized(c, err.Error())
+		}
+		return
+	}
+
+	// 4. 返回成功响应
+	model.Success(c, resp)
+}
+
+// setOAuthStateCookie 写入（maxAge < 0 时清除）第三方登录的 state Cookie
+// 只在该身份提供方的登录路径下发送；身份提供方通过顶层跳转回调，SameSite 使用 Lax
+func setOAuthStateCookie(c *gin.Context, provider, value string, maxAge int) {
+	secure := strings.HasPrefix(config.Config.OAuth.CallbackBaseURL, "https://")
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(service.OAuthStateCookie, value, maxAge, "/api/user/oauth/"+provider, "", secure, true)
+}
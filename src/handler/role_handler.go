@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleHandler 角色权限处理器
+type RoleHandler struct {
+	roleService *service.RoleService
+}
+
+// NewRoleHandler 创建角色权限处理器实例
+func NewRoleHandler() *RoleHandler {
+	return &RoleHandler{
+		roleService: service.NewRoleService(),
+	}
+}
+
+// ListRoles 获取角色列表
+// @Summary      获取角色列表
+// @Description  获取全部角色及其拥有的权限
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  model.Response{data=[]model.Role}  "获取成功"
+// @Failure      403  {object}  model.Response  "无权限"
+// @Failure      500  {object}  model.Response  "服务器错误"
+// @Router       /user/admin/roles [get]
+func (h *RoleHandler) ListRoles(c *gin.Context) {
+	roles, err := h.roleService.ListRoles()
+	if err != nil {
+		model.ServerError(c, "获取角色列表失败: "+err.Error())
+		return
+	}
+
+	model.Success(c, roles)
+}
+
+// GetUserRoles 获取用户角色
+// @Summary      获取用户角色
+// @Description  获取指定用户拥有的角色编码
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int64  true  "用户ID"
+// @Success      200  {object}  model.Response{data=[]string}  "获取成功"
+// @Failure      400  {object}  model.Response  "参数错误"
+// @Failure      403  {object}  model.Response  "无权限"
+// @Router       /user/admin/users/{id}/roles [get]
+func (h *RoleHandler) GetUserRoles(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 调用服务层查询
+	roles, err := h.roleService.GetUserRoles(id)
+	if err != nil {
+		model.ServerError(c, "获取用户角色失败: "+err.Error())
+		return
+	}
+
+	model.Success(c, roles)
+}
+
+// GrantRole 授予用户角色
+// @Summary      授予用户角色
+// @Description  为指定用户授予角色，并吊销其全部会话，用户重新登录后新角色生效
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int64                  true  "用户ID"
+// @Param        request  body      model.UserRoleRequest  true  "角色"
+// @Success      200      {object}  model.Response  "授予成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      403      {object}  model.Response  "无权限"
+// @Failure      404      {object}  model.Response  "用户或角色不存在"
+// @Router       /user/admin/users/{id}/roles [post]
+func (h *RoleHandler) GrantRole(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req model.UserRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用服务层授予角色
+	if err := h.roleService.GrantRole(c, getUserID(c), id, req.Role); err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "授予成功", nil)
+}
+
+// RevokeRole 撤销用户角色
+// @Summary      撤销用户角色
+// @Description  撤销指定用户的角色，并吊销其全部会话使权限立即收回
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id    path      int64   true  "用户ID"
+// @Param        role  path      string  true  "角色编码"
+// @Success      200   {object}  model.Response  "撤销成功"
+// @Failure      400   {object}  model.Response  "参数错误"
+// @Failure      403   {object}  model.Response  "无权限"
+// @Failure      404   {object}  model.Response  "用户或角色不存在"
+// @Router       /user/admin/users/{id}/roles/{role} [delete]
+func (h *RoleHandler) RevokeRole(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 调用服务层撤销角色
+	if err := h.roleService.RevokeRole(c, getUserID(c), id, c.Param("role")); err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "撤销成功", nil)
+}
+
+// respondRoleError 根据角色服务返回的错误输出响应
+func respondRoleError(c *gin.Context, err error) {
+	switch err {
+	case service.ErrUserNotFound, service.ErrRoleNotFound:
+		model.NotFound(c, err.Error())
+	case service.ErrRoleNotRevocable:
+		model.ParamError(c, err.Error())
+	default:
+		model.ServerError(c, "操作失败: "+err.Error())
+	}
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"hi-go/src/model"
+	"hi-go/src/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorHandler 两步验证处理器
+type TwoFactorHandler struct {
+	twoFactorService *service.TwoFactorService
+}
+
+// NewTwoFactorHandler 创建两步验证处理器实例
+func NewTwoFactorHandler() *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: service.NewTwoFactorService(),
+	}
+}
+
+// Enroll 获取两步验证密钥
+// @Summary      获取两步验证密钥
+// @Description  生成新的 TOTP 密钥，使用认证器扫描 otpauth_url 或手动输入 secret 后，调用确认接口开启两步验证
+// @Tags         两步验证
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200  {object}  model.Response{data=model.TwoFactorEnrollResponse}  "获取成功"
+// @Failure      400  {object}  model.Response  "两步验证已开启"
+// @Failure      401  {object}  model.Response  "未授权"
+// @Router       /user/2fa/enroll [post]
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	resp, err := h.twoFactorService.Enroll(getUserID(c))
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	model.Success(c, resp)
+}
+
+// Confirm 确认开启两步验证
+// @Summary      确认开启两步验证
+// @Description  使用认证器中的验证码确认绑定，成功后返回恢复码（仅显示一次，请妥善保存）
+// @Tags         两步验证
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      model.TwoFactorCodeRequest  true  "验证码"
+// @Success      200      {object}  model.Response{data=model.TwoFactorRecoveryCodesResponse}  "开启成功"
+// @Failure      400      {object}  model.Response  "参数错误或验证码错误"
+// @Failure      401      {object}  model.Response  "未授权"
+// @Router       /user/2fa/confirm [post]
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	var req model.TwoFactorCodeRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层确认开启
+	resp, err := h.twoFactorService.Confirm(c, getUserID(c), req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "开启成功", resp)
+}
+
+// Disable 关闭两步验证
+// @Summary      关闭两步验证
+// @Description  使用验证码或恢复码关闭两步验证
+// @Tags         两步验证
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      model.TwoFactorCodeRequest  true  "验证码或恢复码"
+// @Success      200      {object}  model.Response  "关闭成功"
+// @Failure      400      {object}  model.Response  "参数错误或验证码错误"
+// @Failure      401      {object}  model.Response  "未授权"
+// @Router       /user/2fa/disable [post]
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	var req model.TwoFactorCodeRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层关闭
+	if err := h.twoFactorService.Disable(c, getUserID(c), req.Code); err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "关闭成功", nil)
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码
+// @Summary      重新生成恢复码
+// @Description  使用认证器中的验证码重新生成恢复码，旧的恢复码全部作废
+// @Tags         两步验证
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request  body      model.TwoFactorCodeRequest  true  "验证码"
+// @Success      200      {object}  model.Response{data=model.TwoFactorRecoveryCodesResponse}  "生成成功"
+// @Failure      400      {object}  model.Response  "参数错误或验证码错误"
+// @Failure      401      {object}  model.Response  "未授权"
+// @Router       /user/2fa/recovery-codes [post]
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req model.TwoFactorCodeRequest
+
+	// 1. 绑定并验证请求参数
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层重新生成
+	resp, err := h.twoFactorService.RegenerateRecoveryCodes(c, getUserID(c), req.Code)
+	if err != nil {
+		respondTwoFactorError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "生成成功", resp)
+}
+
+// respondTwoFactorError 根据两步验证服务返回的错误输出响应
+func respondTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		model.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrTwoFactorEnabled),
+		errors.Is(err, service.ErrTwoFactorNotEnabled),
+		errors.Is(err, service.ErrTwoFactorNotEnrolled),
+		errors.Is(err, service.ErrTwoFactorInvalidCode):
+		model.ParamError(c, err.Error())
+	default:
+		model.ServerError(c, "操作失败: "+err.Error())
+	}
+}
@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"errors"
+	"hi-go/src/model"
+	"hi-go/src/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler 用户管理处理器（管理员）
+type UserHandler struct {
+	userService *service.UserService
+}
+
+// NewUserHandler 创建用户管理处理器实例
+func NewUserHandler() *UserHandler {
+	return &UserHandler{
+		userService: service.NewUserService(),
+	}
+}
+
+// List 获取用户列表
+// @Summary      获取用户列表
+// @Description  分页查询用户，支持按关键词、状态筛选，deleted=true 时查询已删除的用户
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        keyword    query     string  false  "搜索关键词（用户名、昵称、邮箱、手机号）"
+// @Param        status     query     int     false  "状态：1-正常 0-禁用"
+// @Param        deleted    query     bool    false  "是否只查询已删除的用户"
+// @Param        page       query     int     false  "页码（默认1）"
+// @Param        page_size  query     int     false  "每页数量（默认20，最大100）"
+// @Success      200        {object}  model.Response{data=model.UserListDataResponse}  "获取成功"
+// @Failure      400        {object}  model.Response  "参数错误"
+// @Failure      403        {object}  model.Response  "无权限"
+// @Router       /user/admin/users [get]
+func (h *UserHandler) List(c *gin.Context) {
+	var req model.UserListRequest
+
+	// 1. 绑定查询参数
+	if err := c.ShouldBindQuery(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 2. 调用服务层获取列表
+	resp, err := h.userService.List(&req)
+	if err != nil {
+		model.ServerError(c, "获取列表失败: "+err.Error())
+		return
+	}
+
+	// 3. 返回成功响应
+	model.Success(c, resp)
+}
+
+// GetDetail 获取用户详情
+// @Summary      获取用户详情
+// @Description  获取用户信息及角色，包含已删除的用户
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int64  true  "用户ID"
+// @Success      200  {object}  model.Response{data=model.UserDetailResponse}  "获取成功"
+// @Failure      400  {object}  model.Response  "参数错误"
+// @Failure      404  {object}  model.Response  "用户不存在"
+// @Router       /user/admin/users/{id} [get]
+func (h *UserHandler) GetDetail(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 调用服务层查询
+	resp, err := h.userService.GetDetail(id)
+	if err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	model.Success(c, resp)
+}
+
+// SetStatus 启用或禁用用户
+// @Summary      启用或禁用用户
+// @Description  修改用户状态，禁用后立即吊销该用户的全部会话
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int64                    true  "用户ID"
+// @Param        request  body      model.UserStatusRequest  true  "状态"
+// @Success      200      {object}  model.Response  "操作成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      404      {object}  model.Response  "用户不存在"
+// @Router       /user/admin/users/{id}/status [post]
+func (h *UserHandler) SetStatus(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req model.UserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用服务层修改状态
+	if err := h.userService.SetStatus(c, getUserID(c), id, *req.Status); err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "操作成功", nil)
+}
+
+// ResetPassword 重置用户密码
+// @Summary      重置用户密码
+// @Description  管理员为用户设置新密码，并吊销该用户的全部会话
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int64                       true  "用户ID"
+// @Param        request  body      model.ResetPasswordRequest  true  "新密码"
+// @Success      200      {object}  model.Response  "重置成功"
+// @Failure      400      {object}  model.Response{data=model.FieldErrorsData}  "参数错误或密码不符合策略"
+// @Failure      404      {object}  model.Response  "用户不存在"
+// @Router       /user/admin/users/{id}/reset-password [post]
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用服务层重置密码
+	if err := h.userService.ResetPassword(c, getUserID(c), id, req.Password); err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "重置成功", nil)
+}
+
+// Delete 删除用户
+// @Summary      删除用户
+// @Description  软删除用户，并吊销该用户的全部会话，可通过恢复接口还原
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int64  true  "用户ID"
+// @Success      200  {object}  model.Response  "删除成功"
+// @Failure      400  {object}  model.Response  "参数错误"
+// @Failure      404  {object}  model.Response  "用户不存在"
+// @Router       /user/admin/users/{id} [delete]
+func (h *UserHandler) Delete(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 调用服务层删除
+	if err := h.userService.Delete(c, getUserID(c), id); err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "删除成功", nil)
+}
+
+// Restore 恢复已删除的用户
+// @Summary      恢复用户
+// @Description  恢复已软删除的用户
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id   path      int64  true  "用户ID"
+// @Success      200  {object}  model.Response  "恢复成功"
+// @Failure      400  {object}  model.Response  "参数错误"
+// @Failure      404  {object}  model.Response  "用户不存在"
+// @Router       /user/admin/users/{id}/restore [post]
+func (h *UserHandler) Restore(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 调用服务层恢复
+	if err := h.userService.Restore(c, getUserID(c), id); err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "恢复成功", nil)
+}
+
+// SetRoles 设置用户角色
+// @Summary      设置用户角色
+// @Description  用给定的角色覆盖用户原有角色（user 角色始终保留），并吊销该用户的全部会话
+// @Tags         用户管理
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id       path      int64                   true  "用户ID"
+// @Param        request  body      model.UserRolesRequest  true  "角色列表"
+// @Success      200      {object}  model.Response  "设置成功"
+// @Failure      400      {object}  model.Response  "参数错误"
+// @Failure      404      {object}  model.Response  "用户或角色不存在"
+// @Router       /user/admin/users/{id}/roles [put]
+func (h *UserHandler) SetRoles(c *gin.Context) {
+	// 1. 获取 ID 参数
+	id, err := getInt64Param(c, "id")
+	if err != nil || id == 0 {
+		model.ParamError(c, "无效的用户ID")
+		return
+	}
+
+	// 2. 绑定请求参数
+	var req model.UserRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		model.ParamError(c, "参数错误: "+err.Error())
+		return
+	}
+
+	// 3. 调用服务层设置角色
+	if err := h.userService.SetRoles(c, getUserID(c), id, req.Roles); err != nil {
+		respondUserError(c, err)
+		return
+	}
+
+	model.SuccessWithMessage(c, "设置成功", nil)
+}
+
+// respondUserError 根据用户管理服务返回的错误输出响应
+func respondUserError(c *gin.Context, err error) {
+	if respondPasswordPolicyError(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrUserNotFound), errors.Is(err, service.ErrRoleNotFound):
+		model.NotFound(c, err.Error())
+	case errors.Is(err, service.ErrCannotModifySelf),
+		errors.Is(err, service.ErrUserNotDeleted):
+		model.ParamError(c, err.Error())
+	default:
+		model.ServerError(c, "操作失败: "+err.Error())
+	}
+}
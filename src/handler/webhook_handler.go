@@ -2,6 +2,7 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hi-go/src/model"
 	"hi-go/src/service"
@@ -24,7 +25,7 @@ func NewWebhookHandler() *WebhookHandler {
 
 // Create 创建 webhook
 // @Summary      创建 webhook
-// @Description  创建一个新的 webhook 配置，返回唯一的 webhook URL
+// @Description  创建一个新的 webhook 配置，返回唯一的 webhook URL；event 只能是 home.updated、home.deleted、home.restored、home.purged，回调地址只支持 http、https 且不能指向内网地址
 // @Tags         Webhook 模块
 // @Accept       json
 // @Produce      json
@@ -46,8 +47,12 @@ func (h *WebhookHandler) Create(c *gin.Context) {
 	userID := getUserID(c)
 
 	// 3. 调用服务层创建
-	resp, err := h.webhookService.Create(&req, userID)
+	resp, err := h.webhookService.Create(c, &req, userID)
 	if err != nil {
+		if errors.Is(err, service.ErrWebhookInvalidEvent) || errors.Is(err, service.ErrWebhookInvalidCallbackURL) {
+			model.ParamError(c, err.Error())
+			return
+		}
 		model.ServerError(c, "创建失败: "+err.Error())
 		return
 	}
@@ -58,7 +63,7 @@ func (h *WebhookHandler) Create(c *gin.Context) {
 
 // Update 更新 webhook
 // @Summary      更新 webhook
-// @Description  根据 ID 更新 webhook 配置信息
+// @Description  根据 ID 更新 webhook 配置信息，event 和回调地址的限制与创建接口相同
 // @Tags         Webhook 模块
 // @Accept       json
 // @Produce      json
@@ -81,7 +86,7 @@ func (h *WebhookHandler) Update(c *gin.Context) {
 	userID := getUserID(c)
 
 	// 3. 调用服务层更新
-	resp, err := h.webhookService.Update(&req, userID)
+	resp, err := h.webhookService.Update(c, &req, userID)
 	if err != nil {
 		model.ParamError(c, err.Error())
 		return
@@ -115,7 +120,7 @@ func (h *WebhookHandler) Delete(c *gin.Context) {
 	userID := getUserID(c)
 
 	// 3. 调用服务层删除
-	if err := h.webhookService.Delete(id, userID); err != nil {
+	if err := h.webhookService.Delete(c, id, userID); err != nil {
 		model.ParamError(c, err.Error())
 		return
 	}
@@ -262,7 +267,7 @@ func (h *WebhookHandler) Sign(c *gin.Context) {
 	userID := getUserID(c)
 
 	// 3. 调用服务层生成签名
-	resp, err := h.webhookService.Sign(&req, userID)
+	resp, err := h.webhookService.Sign(c, &req, userID)
 	if err != nil {
 		model.ParamError(c, err.Error())
 		return
@@ -4,17 +4,30 @@ import (
 	"context"
 	"fmt"
 	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/service"
+	"hi-go/src/service/audit"
 	"hi-go/src/utils/jwt"
 	"hi-go/src/utils/logger"
 	redisutil "hi-go/src/utils/redis"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// 会话最后活跃时间的更新间隔
+const sessionTouchInterval = time.Minute
+
 // 认证中间件
-func JWTAuth() gin.HandlerFunc {
+// 默认只接受 Bearer 访问令牌；传入 scopes 时同时接受 "Authorization: ApiKey {key}"，
+// 且 API Key 必须拥有全部指定的作用域
+func JWTAuth(scopes ...string) gin.HandlerFunc {
+	sessionRepo := repository.NewSessionRepository()
+	apiKeyService := service.NewAPIKeyService()
+
 	return func(c *gin.Context) {
 		// 1. 从请求头获取 token
 		authHeader := c.GetHeader("Authorization")
@@ -24,8 +37,12 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
-		// 2. 解析 Bearer token
+		// 2. 解析 Bearer token，API Key 走单独的认证流程
 		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) == 2 && parts[0] == "ApiKey" && len(scopes) > 0 {
+			apiKeyAuth(c, apiKeyService, parts[1], scopes)
+			return
+		}
 		if !(len(parts) == 2 && parts[0] == "Bearer") {
 			model.Unauthorized(c, "Authorization 格式错误，应为: Bearer {token}")
 			c.Abort()
@@ -42,15 +59,21 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
-		// 4. 检查 Redis 中是否存在该 token（防止 token 过期或被删除）
-		redisKey := fmt.Sprintf("jwt:access_token:%s", claims.UserID)
-		storedToken, err := redisutil.Get(context.Background(), redisKey)
+		// 4. 只接受访问令牌（刷新令牌不能直接访问接口）
+		if claims.TokenType != jwt.TokenTypeAccess {
+			model.Unauthorized(c, "Token 类型错误，请使用访问令牌")
+			c.Abort()
+			return
+		}
+
+		// 5. 检查 Redis 中的会话是否存在（防止 token 过期或被吊销）
+		session, err := sessionRepo.Find(context.Background(), claims.UserID, claims.SessionID)
 		if err != nil {
-			// Redis 中找不到 token，说明已过期或被主动删除
+			// Redis 中找不到会话，说明已过期或被主动删除
 			if err == redisutil.ErrKeyNotFound {
-				logger.Warn("Token已过期或不存在",
+				logger.Warn("会话已过期或不存在",
 					zap.String("user_id", claims.UserID),
-					zap.String("redis_key", redisKey))
+					zap.String("session_id", claims.SessionID))
 				model.Unauthorized(c, "Token 已过期，请重新登录")
 				c.Abort()
 				return
@@ -64,22 +87,131 @@ func JWTAuth() gin.HandlerFunc {
 			return
 		}
 
-		// 5. 验证 Redis 中的 token 与请求的 token 是否一致
-		if storedToken != tokenString {
+		// 6. 验证请求的 token 是否为会话当前的访问令牌
+		if session.AccessTokenID != claims.ID {
 			logger.Warn("Token不匹配",
 				zap.String("user_id", claims.UserID),
-				zap.String("redis_key", redisKey))
+				zap.String("session_id", claims.SessionID))
+			model.Unauthorized(c, "Token 无效，请重新登录")
+			c.Abort()
+			return
+		}
+
+		// 7. 代登录令牌必须对应同一管理员创建的代登录会话
+		impersonatorID := ""
+		actor, impersonated := claims.Impersonator()
+		if impersonated {
+			impersonatorID = actor.UserID
+		}
+		if session.ImpersonatorID != impersonatorID {
+			logger.Warn("代登录信息与会话不一致",
+				zap.String("user_id", claims.UserID),
+				zap.String("session_id", claims.SessionID))
 			model.Unauthorized(c, "Token 无效，请重新登录")
 			c.Abort()
 			return
 		}
 
-		// 6. 将用户信息存入上下文
+		// 8. 更新会话最后活跃时间（限频，避免每个请求都写 Redis）
+		if now := time.Now(); now.Sub(session.LastSeenAt) > sessionTouchInterval {
+			if err := sessionRepo.Touch(context.Background(), claims.UserID, claims.SessionID, now); err != nil {
+				logger.Warn("更新会话活跃时间失败",
+					zap.String("session_id", claims.SessionID),
+					zap.Error(err))
+			}
+		}
+
+		// 9. 将用户信息存入上下文，代登录时额外记录实际操作的管理员
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("roles", claims.Roles)
+		c.Set("sessionID", claims.SessionID)
+		if impersonated {
+			c.Set("impersonatorID", actor.UserID)
+			c.Set("impersonatorName", actor.Username)
+		}
+
+		// 10. 继续处理请求
+		c.Next()
+
+		// 11. 代登录期间的每个请求都写入审计日志
+		if impersonated {
+			adminID, _ := strconv.ParseInt(actor.UserID, 10, 64)
+			audit.Record(c, adminID, audit.ActionImpersonatedRequest, "user:"+claims.UserID, map[string]interface{}{
+				"method": c.Request.Method,
+				"path":   c.FullPath(),
+				"url":    c.Request.URL.Path,
+				"status": c.Writer.Status(),
+			})
+		}
+	}
+}
+
+// 禁止代登录中间件，用于修改密码、注销账号等敏感接口
+// 管理员代登录时只能查看和操作业务数据，不能修改用户的登录凭证
+func DenyImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, impersonated := c.Get("impersonatorID"); impersonated {
+			model.Forbidden(c, "代登录状态下不允许此操作")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// apiKeyAuth 使用 API Key 认证，并检查作用域
+func apiKeyAuth(c *gin.Context, apiKeyService *service.APIKeyService, rawKey string, scopes []string) {
+	principal, err := apiKeyService.Authenticate(rawKey, c.ClientIP())
+	if err != nil {
+		model.Unauthorized(c, "API Key 验证失败: "+err.Error())
+		c.Abort()
+		return
+	}
+
+	for _, scope := range scopes {
+		if !principal.Key.HasScope(scope) {
+			model.Forbidden(c, "API Key 缺少作用域: "+scope)
+			c.Abort()
+			return
+		}
+	}
+
+	// 将用户信息存入上下文，apiKeyID 用于区分 API Key 调用
+	c.Set("userID", fmt.Sprintf("%d", principal.User.ID))
+	c.Set("username", principal.User.Username)
+	c.Set("roles", principal.Roles)
+	c.Set("apiKeyID", principal.Key.ID)
+	c.Set("apiKeyScopes", principal.Key.Scopes)
+
+	c.Next()
+}
+
+// 作用域中间件，用于在 JWTAuth 之后对具体接口追加作用域要求
+// 只约束 API Key 调用，使用访问令牌登录的用户不受影响
+func RequireScope(scopes ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isAPIKey := c.Get("apiKeyID"); !isAPIKey {
+			c.Next()
+			return
+		}
+
+		keyScopes := c.GetStringSlice("apiKeyScopes")
+		for _, scope := range scopes {
+			found := false
+			for _, s := range keyScopes {
+				if s == scope {
+					found = true
+					break
+				}
+			}
+			if !found {
+				model.Forbidden(c, "API Key 缺少作用域: "+scope)
+				c.Abort()
+				return
+			}
+		}
 
-		// 7. 继续处理请求
 		c.Next()
 	}
 }
@@ -120,3 +252,39 @@ func RoleAuth(allowedRoles ...string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// 权限中间件，要求当前用户的角色拥有全部指定权限
+// 用户的角色取自令牌中签发时的角色快照，角色对应的权限实时从数据库查询：
+// 调整某个角色的权限立即生效；授予、撤销用户角色会吊销该用户的全部会话，重新登录后按新角色签发令牌
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	roleService := service.NewRoleService()
+
+	return func(c *gin.Context) {
+		// 获取用户角色
+		userRoles := c.GetStringSlice("roles")
+		if len(userRoles) == 0 {
+			model.Forbidden(c, "权限不足")
+			c.Abort()
+			return
+		}
+
+		// 检查是否拥有权限
+		ok, err := roleService.HasPermissions(userRoles, permissions...)
+		if err != nil {
+			logger.Error("权限查询失败",
+				zap.Strings("roles", userRoles),
+				zap.Error(err))
+			model.ServerError(c, "权限校验失败")
+			c.Abort()
+			return
+		}
+
+		if !ok {
+			model.Forbidden(c, "权限不足，需要权限: "+strings.Join(permissions, ", "))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
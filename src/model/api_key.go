@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// API Key 作用域，限制 API Key 可以访问的接口
+const (
+	ScopeWebhook   = "webhook"    // Webhook 管理
+	ScopeHomeRead  = "home:read"  // 读取首页内容
+	ScopeHomeWrite = "home:write" // 编辑首页内容，需同时拥有 home:read，且用户角色仍需拥有 home:write 权限
+)
+
+// APIKey 个人 API Key，用于脚本、CI 等机器调用
+// 数据库只保存 Key 的 SHA-256 摘要，明文仅在创建时返回一次
+type APIKey struct {
+	ID         int64      `gorm:"primarykey;autoIncrement:false" json:"id"`
+	UserID     int64      `gorm:"index;not null" json:"user_id"`
+	Name       string     `gorm:"type:varchar(100);not null" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16);not null" json:"prefix"` // Key 前缀，便于用户辨认
+	KeyHash    string     `gorm:"type:char(64);uniqueIndex;not null" json:"-"`
+	Scopes     []string   `gorm:"type:varchar(255);serializer:json" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"` // 过期时间，为空表示永不过期
+	LastUsedAt *time.Time `json:"last_used_at"`
+	LastUsedIP string     `gorm:"type:varchar(64)" json:"last_used_ip"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// HasScope 是否拥有指定作用域
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyCreateRequest 创建 API Key 请求
+type APIKeyCreateRequest struct {
+	Name      string     `json:"name" binding:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1,dive,oneof=webhook home:read home:write"` // 作用域
+	ExpiresAt *time.Time `json:"expires_at"`                                                              // 过期时间（可选），为空表示永不过期
+}
+
+// APIKeyCreateResponse 创建 API Key 响应
+type APIKeyCreateResponse struct {
+	*APIKey
+	Key string `json:"key"` // API Key 明文，仅返回一次，请妥善保存
+}
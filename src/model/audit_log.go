@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// AuditLog 安全审计日志，记录登录和管理操作
+// 只新增不修改，ActorID 为 0 表示匿名操作（如登录失败）
+type AuditLog struct {
+	ID             int64                  `gorm:"primarykey;autoIncrement:false" json:"id"`
+	ActorID        int64                  `gorm:"index:idx_actor_time" json:"actor_id"`          // 操作人用户ID
+	Action         string                 `gorm:"type:varchar(64);index;not null" json:"action"` // 操作类型，如 auth.login
+	Target         string                 `gorm:"type:varchar(100)" json:"target"`               // 操作对象，如 user:123、webhook:456
+	Metadata       map[string]interface{} `gorm:"type:text;serializer:json" json:"metadata"`     // 附加信息
+	APIKeyID       int64                  `json:"api_key_id,omitempty"`                          // 通过 API Key 调用时的 Key ID
+	ImpersonatorID int64                  `gorm:"index" json:"impersonator_id,omitempty"`        // 代登录期间的操作，记录实际操作的管理员ID
+	IP             string                 `gorm:"type:varchar(64)" json:"ip"`                    // 客户端IP
+	UserAgent      string                 `gorm:"type:varchar(255)" json:"user_agent"`           // 客户端 User-Agent
+	TraceID        string                 `gorm:"type:varchar(64);index" json:"trace_id"`        // 请求追踪ID
+	CreatedAt      time.Time              `gorm:"index;index:idx_actor_time" json:"created_at"`  // 操作时间
+}
+
+// 指定表名
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditLogListRequest 审计日志查询请求（管理员）
+type AuditLogListRequest struct {
+	ActorID   int64      `form:"actor_id" binding:"omitempty,min=1"`                            // 操作人用户ID
+	Action    string     `form:"action" binding:"omitempty,max=64"`                             // 操作类型，以 . 结尾时按前缀匹配，如 webhook.
+	StartTime *time.Time `form:"start_time" time_format:"2006-01-02 15:04:05" time_utc:"false"` // 开始时间（含）
+	EndTime   *time.Time `form:"end_time" time_format:"2006-01-02 15:04:05" time_utc:"false"`   // 结束时间（不含）
+	Page      int        `form:"page" binding:"omitempty,min=1"`                                // 页码
+	PageSize  int        `form:"page_size" binding:"omitempty,min=1"`                           // 每页数量
+}
+
+// AuditLogListDataResponse 审计日志列表data字段
+type AuditLogListDataResponse struct {
+	List  []AuditLog `json:"list"`  // 列表数据
+	Total int64      `json:"total"` // 总数
+}
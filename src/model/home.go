@@ -1,18 +1,28 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Home 首页内容模型
 type Home struct {
-	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
-	Title       string    `gorm:"type:varchar(200);not null" json:"title"` // 标题
-	Description string    `gorm:"type:varchar(500)" json:"description"`    // 描述
-	ImageURL    string    `gorm:"type:varchar(500)" json:"image_url"`      // 图片URL
-	Link        string    `gorm:"type:varchar(500)" json:"link"`           // 链接
-	Sort        int       `gorm:"default:0" json:"sort"`                   // 排序（越小越靠前）
-	Status      int       `gorm:"default:1" json:"status"`                 // 状态：1-启用 0-禁用
-	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`        // 创建时间
-	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`        // 更新时间
+	ID          int64          `gorm:"primaryKey;autoIncrement" json:"id"`
+	Title       string         `gorm:"type:varchar(200);not null" json:"title"`             // 标题
+	Description string         `gorm:"type:varchar(500)" json:"description"`                // 描述
+	ImageURL    string         `gorm:"type:varchar(500)" json:"image_url"`                  // 图片URL
+	Link        string         `gorm:"type:varchar(500)" json:"link"`                       // 链接
+	Sort        int            `gorm:"default:0" json:"sort"`                               // 排序（越小越靠前）
+	Status      int            `gorm:"not null" json:"status"`                              // 状态：1-启用 0-禁用（不设默认值，否则 gorm 创建时会把 0 替换为默认值）
+	PublishAt   *time.Time     `gorm:"index" json:"publish_at,omitempty"`                   // 开始展示时间，为空表示立即展示
+	UnpublishAt *time.Time     `gorm:"index" json:"unpublish_at,omitempty"`                 // 结束展示时间，为空表示一直展示
+	Version     int            `gorm:"not null;default:1" json:"version"`                   // 版本号，每次修改递增，用于乐观锁
+	SlotID      *int64         `gorm:"index" json:"slot_id,omitempty"`                      // 所属版位ID，为空表示未分配版位
+	Tags        []HomeTag      `gorm:"many2many:home_tag_relations;" json:"tags,omitempty"` // 标签
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`                    // 创建时间
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updated_at"`                    // 更新时间
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`                                      // 删除时间（移入回收站）
 }
 
 // TableName 指定表名
@@ -22,8 +32,10 @@ func (Home) TableName() string {
 
 // HomeListRequest 首页列表请求
 type HomeListRequest struct {
-	Page     int `form:"page" binding:"omitempty,min=1"`      // 页码
-	PageSize int `form:"page_size" binding:"omitempty,min=1"` // 每页数量
+	Slot     string `form:"slot" binding:"omitempty,max=50"`     // 版位编码，指定时按版位的排序方式和展示条数返回
+	Tag      string `form:"tag" binding:"omitempty,max=50"`      // 标签名称
+	Page     int    `form:"page" binding:"omitempty,min=1"`      // 页码
+	PageSize int    `form:"page_size" binding:"omitempty,min=1"` // 每页数量
 }
 
 // HomeListDataResponse 首页列表data字段
@@ -32,15 +44,32 @@ type HomeListDataResponse struct {
 	Total int64  `json:"total"` // 总数
 }
 
+// HomeFields 首页内容可编辑字段，更新和批量导入使用同一套校验规则
+type HomeFields struct {
+	Title       string     `json:"title" binding:"omitempty,max=200"`       // 标题
+	Description string     `json:"description" binding:"omitempty,max=500"` // 描述
+	ImageURL    string     `json:"image_url" binding:"omitempty,max=500"`   // 图片URL
+	Link        string     `json:"link" binding:"omitempty,max=500"`        // 链接
+	Sort        *int       `json:"sort" binding:"omitempty,min=0"`          // 排序
+	Status      *int       `json:"status" binding:"omitempty,oneof=0 1"`    // 状态
+	PublishAt   *time.Time `json:"publish_at"`                              // 开始展示时间（RFC3339）
+	UnpublishAt *time.Time `json:"unpublish_at"`                            // 结束展示时间（RFC3339），须晚于开始展示时间
+}
+
 // HomeUpdateRequest 首页更新请求
 type HomeUpdateRequest struct {
-	ID          int64  `json:"id" binding:"required"`                     // 首页内容ID
-	Title       string `json:"title" binding:"omitempty,max=200"`        // 标题
-	Description string `json:"description" binding:"omitempty,max=500"`  // 描述
-	ImageURL    string `json:"image_url" binding:"omitempty,max=500"`    // 图片URL
-	Link        string `json:"link" binding:"omitempty,max=500"`         // 链接
-	Sort        *int   `json:"sort" binding:"omitempty,min=0"`          // 排序
-	Status      *int   `json:"status" binding:"omitempty,oneof=0 1"`    // 状态
+	ID int64 `json:"id" binding:"required"` // 首页内容ID
+	HomeFields
+	ClearSchedule bool     `json:"clear_schedule"`                                       // 清除开始、结束展示时间，恢复为一直展示
+	SlotID        *int64   `json:"slot_id" binding:"omitempty,min=0"`                    // 所属版位ID，0 表示移出版位
+	Tags          []string `json:"tags" binding:"omitempty,max=20,dive,required,max=50"` // 标签名称（覆盖原有标签），不传表示不修改，传空数组表示清空
+}
+
+// HomePreviewRequest 首页预览请求（编辑人员查看尚未上线的内容）
+type HomePreviewRequest struct {
+	At       *time.Time `form:"at" time_format:"2006-01-02 15:04:05" time_utc:"false"` // 预览的时间点，为空时显示当前有效及尚未上线的全部内容
+	Page     int        `form:"page" binding:"omitempty,min=1"`                        // 页码
+	PageSize int        `form:"page_size" binding:"omitempty,min=1"`                   // 每页数量
 }
 
 // HomeDeleteRequest 首页删除请求
@@ -50,12 +79,118 @@ type HomeDeleteRequest struct {
 
 // HomeSearchRequest 首页搜索请求
 type HomeSearchRequest struct {
-	Keyword  string `form:"keyword" binding:"omitempty"`            // 搜索关键词（标题或描述）
-	Page     int    `form:"page" binding:"omitempty,min=1"`         // 页码
-	PageSize int    `form:"page_size" binding:"omitempty,min=1"`    // 每页数量
+	Keyword  string `form:"keyword" binding:"omitempty"`         // 搜索关键词（标题或描述）
+	Slot     string `form:"slot" binding:"omitempty,max=50"`     // 版位编码
+	Tag      string `form:"tag" binding:"omitempty,max=50"`      // 标签名称
+	Page     int    `form:"page" binding:"omitempty,min=1"`      // 页码
+	PageSize int    `form:"page_size" binding:"omitempty,min=1"` // 每页数量
+}
+
+// HomeSearchItem 首页搜索结果项
+type HomeSearchItem struct {
+	Home
+	Highlight map[string]string `json:"highlight,omitempty"` // 高亮片段（字段名 -> 含 <em> 标签、其余内容已做 HTML 转义的片段），仅 ES 检索时返回
+}
+
+// HomeSearchDataResponse 首页搜索data字段
+type HomeSearchDataResponse struct {
+	List   []HomeSearchItem `json:"list"`   // 列表数据
+	Total  int64            `json:"total"`  // 总数
+	Engine string           `json:"engine"` // 检索引擎：elasticsearch 或 mysql（ES 不可用时回退）
 }
 
 // HomeGetByIDRequest 首页根据ID查询请求
 type HomeGetByIDRequest struct {
 	ID int64 `form:"id" binding:"required"` // 首页内容ID
 }
+
+// HomeRestoreRequest 从回收站恢复首页内容请求
+type HomeRestoreRequest struct {
+	ID int64 `json:"id" binding:"required"` // 首页内容ID
+}
+
+// HomePurgeRequest 彻底删除回收站中的首页内容请求（管理员）
+type HomePurgeRequest struct {
+	ID int64 `json:"id" binding:"required"` // 首页内容ID
+}
+
+// HomeTrashItem 回收站中的首页内容
+type HomeTrashItem struct {
+	Home
+	DeletedAt time.Time  `json:"deleted_at"`         // 删除时间
+	PurgeAt   *time.Time `json:"purge_at,omitempty"` // 预计彻底删除时间，未开启自动清理时为空
+}
+
+// HomeTrashListDataResponse 回收站列表data字段
+type HomeTrashListDataResponse struct {
+	List  []HomeTrashItem `json:"list"`  // 列表数据
+	Total int64           `json:"total"` // 总数
+}
+
+// HomeImportRequest 首页批量导入请求（multipart/form-data，文件字段为 file）
+type HomeImportRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv json xlsx"` // 文件格式，为空时按文件扩展名判断
+	DryRun bool   `form:"dry_run"`                                        // 只校验不写入
+}
+
+// HomeImportError 导入失败的行
+type HomeImportError struct {
+	Row     int    `json:"row"`             // 行号：CSV/XLSX 为表格中的行号（含表头），JSON 为数组下标加一
+	Field   string `json:"field,omitempty"` // 字段名，整行错误时为空
+	Message string `json:"message"`         // 错误信息
+}
+
+// HomeImportResult 首页批量导入结果data字段
+type HomeImportResult struct {
+	Total    int               `json:"total"`    // 文件中的数据行数
+	Imported int               `json:"imported"` // 实际写入的行数，试运行或存在错误时为 0
+	DryRun   bool              `json:"dry_run"`  // 是否为试运行
+	Errors   []HomeImportError `json:"errors"`   // 校验失败的行，存在错误时不写入任何数据
+}
+
+// HomeExportRequest 首页导出请求
+type HomeExportRequest struct {
+	Format  string `form:"format" binding:"omitempty,oneof=csv json xlsx"` // 导出格式，默认 csv
+	Keyword string `form:"keyword" binding:"omitempty,max=100"`            // 关键词（标题或描述）
+	Status  *int   `form:"status" binding:"omitempty,oneof=0 1"`           // 状态，为空表示全部
+}
+
+// HomeReorderItem 调整排序的一项
+type HomeReorderItem struct {
+	ID      int64 `json:"id" binding:"required"`      // 首页内容ID
+	Version int   `json:"version" binding:"required"` // 读取列表时的版本号，与当前版本不一致时拒绝调整
+}
+
+// HomeReorderRequest 首页内容调整排序请求
+type HomeReorderRequest struct {
+	Items []HomeReorderItem `json:"items" binding:"required,min=1,max=500,dive"` // 按新顺序排列的内容（排在前面的展示在前）
+}
+
+// HomeSortVersion 首页内容的排序和版本号
+type HomeSortVersion struct {
+	ID      int64 `json:"id"`      // 首页内容ID
+	Sort    int   `json:"sort"`    // 排序
+	Version int   `json:"version"` // 版本号
+}
+
+// HomeReorderResult 调整排序结果data字段
+// 成功时为调整后的排序和版本号；版本冲突时为发生冲突的内容的最新排序和版本号
+type HomeReorderResult struct {
+	Items []HomeSortVersion `json:"items"`
+}
+
+// HomeCacheCounter 缓存命中统计
+type HomeCacheCounter struct {
+	Hits    int64   `json:"hits"`     // 命中次数
+	Misses  int64   `json:"misses"`   // 未命中次数
+	Loads   int64   `json:"loads"`    // 实际查询数据库次数，并发未命中的请求只查询一次
+	HitRate float64 `json:"hit_rate"` // 命中率
+}
+
+// HomeCacheStatsResponse 首页缓存统计data字段（当前实例自启动以来的数据）
+type HomeCacheStatsResponse struct {
+	Enabled bool             `json:"enabled"` // 是否开启缓存
+	TTL     int              `json:"ttl"`     // 缓存时长（秒）
+	List    HomeCacheCounter `json:"list"`    // 首页列表
+	Detail  HomeCacheCounter `json:"detail"`  // 首页内容详情
+}
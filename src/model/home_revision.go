@@ -0,0 +1,101 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 首页内容修订类型
+const (
+	HomeRevisionInitial  = "initial"  // 首次修改前的原始内容
+	HomeRevisionUpdate   = "update"   // 修改
+	HomeRevisionRollback = "rollback" // 回滚到历史版本
+)
+
+// HomeSnapshot 首页内容快照（可编辑字段）
+type HomeSnapshot struct {
+	Title       string     `json:"title"`                  // 标题
+	Description string     `json:"description"`            // 描述
+	ImageURL    string     `json:"image_url"`              // 图片URL
+	Link        string     `json:"link"`                   // 链接
+	Sort        int        `json:"sort"`                   // 排序
+	Status      int        `json:"status"`                 // 状态
+	PublishAt   *time.Time `json:"publish_at,omitempty"`   // 开始展示时间
+	UnpublishAt *time.Time `json:"unpublish_at,omitempty"` // 结束展示时间
+	SlotID      *int64     `json:"slot_id,omitempty"`      // 所属版位ID
+	Tags        []string   `json:"tags"`                   // 标签名称
+}
+
+// NewHomeSnapshot 生成首页内容快照
+func NewHomeSnapshot(home *Home) HomeSnapshot {
+	tags := make([]string, 0, len(home.Tags))
+	for _, tag := range home.Tags {
+		tags = append(tags, tag.Name)
+	}
+	return HomeSnapshot{
+		Title:       home.Title,
+		Description: home.Description,
+		ImageURL:    home.ImageURL,
+		Link:        home.Link,
+		Sort:        home.Sort,
+		Status:      home.Status,
+		PublishAt:   home.PublishAt,
+		UnpublishAt: home.UnpublishAt,
+		SlotID:      home.SlotID,
+		Tags:        tags,
+	}
+}
+
+// HomeRevision 首页内容修订记录，每次修改保存一份修改后的完整快照
+type HomeRevision struct {
+	ID           int64        `gorm:"primaryKey;autoIncrement" json:"id"`
+	HomeID       int64        `gorm:"uniqueIndex:idx_home_revision;not null" json:"home_id"` // 首页内容ID
+	Version      int          `gorm:"uniqueIndex:idx_home_revision;not null" json:"version"` // 修改后的内容版本号
+	Action       string       `gorm:"type:varchar(20);not null" json:"action"`               // 修订类型：initial/update/rollback
+	Snapshot     HomeSnapshot `gorm:"type:json;serializer:json" json:"snapshot"`             // 内容快照
+	AuthorID     int64        `gorm:"index" json:"author_id"`                                // 修改人ID，原始内容为 0
+	RollbackFrom *int64       `json:"rollback_from,omitempty"`                               // 回滚时为目标修订记录ID
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// TableName 指定表名
+func (HomeRevision) TableName() string {
+	return "home_revisions"
+}
+
+// HomeRevisionListRequest 修订记录列表请求
+type HomeRevisionListRequest struct {
+	Page     int `form:"page" binding:"omitempty,min=1"`      // 页码
+	PageSize int `form:"page_size" binding:"omitempty,min=1"` // 每页数量
+}
+
+// HomeRevisionListDataResponse 修订记录列表data字段
+type HomeRevisionListDataResponse struct {
+	List  []HomeRevision `json:"list"`  // 列表数据（最新的在前）
+	Total int64          `json:"total"` // 总数
+}
+
+// HomeRevisionDiffRequest 比较两个修订记录请求
+type HomeRevisionDiffRequest struct {
+	From int64 `form:"from" binding:"required"` // 原修订记录ID
+	To   int64 `form:"to" binding:"required"`   // 新修订记录ID
+}
+
+// HomeRevisionChange 字段变化
+type HomeRevisionChange struct {
+	Field string          `json:"field"` // 字段名
+	From  json.RawMessage `json:"from"`  // 原值
+	To    json.RawMessage `json:"to"`    // 新值
+}
+
+// HomeRevisionDiffResponse 比较结果data字段
+type HomeRevisionDiffResponse struct {
+	From    HomeRevision         `json:"from"`    // 原修订记录
+	To      HomeRevision         `json:"to"`      // 新修订记录
+	Changes []HomeRevisionChange `json:"changes"` // 有变化的字段，无变化时为空数组
+}
+
+// HomeRollbackRequest 回滚请求
+type HomeRollbackRequest struct {
+	RevisionID int64 `json:"revision_id" binding:"required"` // 回滚到的修订记录ID
+}
@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// 版位内容排序方式
+const (
+	HomeSlotSortManual = "sort"   // 按排序值从小到大（可通过调整排序接口拖拽）
+	HomeSlotSortNewest = "newest" // 按开始展示时间倒序，未设置时按创建时间
+)
+
+// HomeSlot 首页版位（如轮播、宫格、弹窗），每条首页内容最多属于一个版位
+type HomeSlot struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code        string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"code"`        // 版位编码，客户端按编码获取列表，创建后不可修改
+	Name        string    `gorm:"type:varchar(50);not null" json:"name"`                    // 版位名称
+	Description string    `gorm:"type:varchar(255)" json:"description"`                     // 描述
+	SortOrder   string    `gorm:"type:varchar(20);not null;default:sort" json:"sort_order"` // 内容排序方式：sort-按排序值 newest-最新在前
+	MaxItems    int       `gorm:"not null;default:0" json:"max_items"`                      // 最多展示的内容条数，0 表示不限
+	Status      int       `gorm:"not null" json:"status"`                                   // 状态：1-启用 0-禁用（禁用后列表为空；不设默认值，否则 gorm 创建时会把 0 替换为默认值）
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (HomeSlot) TableName() string {
+	return "home_slots"
+}
+
+// HomeTag 首页内容标签，修改内容时按名称自动创建
+type HomeTag struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Name      string    `gorm:"type:varchar(50);uniqueIndex;not null" json:"name"` // 标签名称
+	CreatedAt time.Time `json:"-"`
+}
+
+// TableName 指定表名
+func (HomeTag) TableName() string {
+	return "home_tags"
+}
+
+// HomeSlotCreateRequest 创建版位请求
+type HomeSlotCreateRequest struct {
+	Code        string `json:"code" binding:"required,max=50"`                   // 版位编码，如 carousel
+	Name        string `json:"name" binding:"required,max=50"`                   // 版位名称
+	Description string `json:"description" binding:"omitempty,max=255"`          // 描述
+	SortOrder   string `json:"sort_order" binding:"omitempty,oneof=sort newest"` // 内容排序方式，默认 sort
+	MaxItems    int    `json:"max_items" binding:"omitempty,min=0,max=1000"`     // 最多展示的内容条数，0 表示不限
+	Status      *int   `json:"status" binding:"omitempty,oneof=0 1"`             // 状态，默认启用
+}
+
+// HomeSlotUpdateRequest 修改版位请求（编码不可修改）
+type HomeSlotUpdateRequest struct {
+	ID          int64  `json:"id" binding:"required"`                            // 版位ID
+	Name        string `json:"name" binding:"omitempty,max=50"`                  // 版位名称
+	Description string `json:"description" binding:"omitempty,max=255"`          // 描述
+	SortOrder   string `json:"sort_order" binding:"omitempty,oneof=sort newest"` // 内容排序方式
+	MaxItems    *int   `json:"max_items" binding:"omitempty,min=0,max=1000"`     // 最多展示的内容条数，0 表示不限
+	Status      *int   `json:"status" binding:"omitempty,oneof=0 1"`             // 状态
+}
+
+// HomeSlotDeleteRequest 删除版位请求，版位下的内容变为未分配版位
+type HomeSlotDeleteRequest struct {
+	ID int64 `json:"id" binding:"required"` // 版位ID
+}
+
+// HomeSlotGetByIDRequest 版位详情请求
+type HomeSlotGetByIDRequest struct {
+	ID int64 `form:"id" binding:"required"` // 版位ID
+}
+
+// HomeSlotItem 版位及其下的内容数量
+type HomeSlotItem struct {
+	HomeSlot
+	ItemCount int64 `json:"item_count"` // 版位下未删除的内容数量（含禁用和不在展示时间内的）
+}
+
+// HomeSlotListDataResponse 版位列表data字段
+type HomeSlotListDataResponse struct {
+	List []HomeSlotItem `json:"list"` // 列表数据
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// 首页内容统计事件类型
+const (
+	HomeEventImpression = "impression" // 曝光
+	HomeEventClick      = "click"      // 点击
+)
+
+// HomeDailyStat 首页内容每日曝光点击统计，由定时任务从 Redis 汇总写入
+type HomeDailyStat struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	HomeID      int64     `gorm:"uniqueIndex:idx_home_daily_stat;not null" json:"home_id"`                   // 首页内容ID
+	StatDate    time.Time `gorm:"type:date;uniqueIndex:idx_home_daily_stat;index;not null" json:"stat_date"` // 统计日期
+	Impressions int64     `gorm:"not null;default:0" json:"impressions"`                                     // 曝光次数
+	Clicks      int64     `gorm:"not null;default:0" json:"clicks"`                                          // 点击次数
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (HomeDailyStat) TableName() string {
+	return "home_daily_stats"
+}
+
+// HomeTrackEvent 单个曝光/点击事件
+type HomeTrackEvent struct {
+	ID    int64  `json:"id" binding:"required,min=1"`                     // 首页内容ID
+	Event string `json:"event" binding:"required,oneof=impression click"` // 事件类型：impression/click
+}
+
+// HomeTrackRequest 上报曝光/点击事件请求，一次可上报多条（如列表整屏曝光）
+type HomeTrackRequest struct {
+	Events []HomeTrackEvent `json:"events" binding:"required,min=1,max=100,dive"` // 事件列表
+}
+
+// HomeStatsReportRequest 曝光点击报表请求
+type HomeStatsReportRequest struct {
+	StartDate string `form:"start_date" binding:"required,datetime=2006-01-02"` // 开始日期（含）
+	EndDate   string `form:"end_date" binding:"required,datetime=2006-01-02"`   // 结束日期（含）
+	ID        int64  `form:"id" binding:"omitempty,min=1"`                      // 只看指定内容
+	Page      int    `form:"page" binding:"omitempty,min=1"`                    // 页码
+	PageSize  int    `form:"page_size" binding:"omitempty,min=1"`               // 每页数量
+}
+
+// HomeStatsItem 单个内容在日期范围内的汇总数据
+type HomeStatsItem struct {
+	HomeID      int64   `json:"home_id"`     // 首页内容ID
+	Title       string  `json:"title"`       // 标题
+	Impressions int64   `json:"impressions"` // 曝光次数
+	Clicks      int64   `json:"clicks"`      // 点击次数
+	CTR         float64 `json:"ctr"`         // 点击率（点击/曝光），无曝光时为 0
+}
+
+// HomeStatsReportResponse 曝光点击报表data字段
+type HomeStatsReportResponse struct {
+	List  []HomeStatsItem `json:"list"`  // 列表数据（按曝光次数倒序）
+	Total int64           `json:"total"` // 有数据的内容总数
+}
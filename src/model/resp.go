@@ -2,6 +2,7 @@ package model
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -24,6 +25,8 @@ const (
 	CodeForbidden    = 1003 // 无权限
 	CodeNotFound     = 1004 // 资源不存在
 	CodeServerError  = 1005 // 服务器错误
+	CodeTooManyTries = 1006 // 尝试次数过多，已被临时锁定
+	CodeConflict     = 1007 // 数据已被他人修改（并发冲突）
 )
 
 // 存储在 gin.Context 中的 TraceID 键名
@@ -107,3 +110,36 @@ func NotFound(c *gin.Context, message string) {
 func ServerError(c *gin.Context, message string) {
 	Error(c, http.StatusInternalServerError, CodeServerError, message)
 }
+
+// 并发冲突响应（附带最新数据，客户端据此刷新后重试）
+func Conflict(c *gin.Context, message string, data interface{}) {
+	ErrorWithData(c, http.StatusConflict, CodeConflict, message, data)
+}
+
+// RetryAfterData 锁定类错误的 data 字段
+type RetryAfterData struct {
+	RetryAfter int64 `json:"retry_after" example:"900"` // 距离可以重试的秒数
+}
+
+// 尝试次数过多响应（附带 Retry-After 头和重试时间）
+func TooManyTries(c *gin.Context, message string, retryAfter int64) {
+	c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+	ErrorWithData(c, http.StatusTooManyRequests, CodeTooManyTries, message, RetryAfterData{RetryAfter: retryAfter})
+}
+
+// FieldError 单个字段的校验失败详情
+type FieldError struct {
+	Field   string `json:"field" example:"password"`                // 字段名
+	Code    string `json:"code" example:"common_password"`          // 失败原因
+	Message string `json:"message" example:"该密码过于常见或已在泄露数据中出现，请更换"` // 提示信息
+}
+
+// FieldErrorsData 字段校验失败时的 data 字段
+type FieldErrorsData struct {
+	Errors []FieldError `json:"errors"` // 逐项的失败详情
+}
+
+// 参数错误响应（附带逐个字段的失败详情）
+func FieldErrors(c *gin.Context, message string, errs []FieldError) {
+	ErrorWithData(c, http.StatusBadRequest, CodeParamError, message, FieldErrorsData{Errors: errs})
+}
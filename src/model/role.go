@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+// 内置角色编码
+const (
+	RoleUser          = "user"           // 普通用户（所有用户默认拥有）
+	RoleContentEditor = "content_editor" // 内容编辑
+	RoleAdmin         = "admin"          // 管理员
+)
+
+// 内置权限编码
+const (
+	PermissionHomeWrite  = "home:write"  // 创建、修改、删除首页内容
+	PermissionUserManage = "user:manage" // 管理用户
+)
+
+// Role 角色模型
+type Role struct {
+	ID          int64        `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code        string       `gorm:"type:varchar(50);uniqueIndex;not null" json:"code"` // 角色编码，写入 JWT 的 roles
+	Name        string       `gorm:"type:varchar(50);not null" json:"name"`             // 角色名称
+	Description string       `gorm:"type:varchar(255)" json:"description"`              // 描述
+	Permissions []Permission `gorm:"many2many:role_permissions;" json:"permissions,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// 指定表名
+func (Role) TableName() string {
+	return "roles"
+}
+
+// Permission 权限模型
+type Permission struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Code        string    `gorm:"type:varchar(100);uniqueIndex;not null" json:"code"` // 权限编码，如 home:write
+	Name        string    `gorm:"type:varchar(50);not null" json:"name"`              // 权限名称
+	Description string    `gorm:"type:varchar(255)" json:"description"`               // 描述
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// 指定表名
+func (Permission) TableName() string {
+	return "permissions"
+}
+
+// UserRole 用户角色关联
+type UserRole struct {
+	UserID    int64     `gorm:"primaryKey;autoIncrement:false" json:"user_id"`
+	RoleID    int64     `gorm:"primaryKey;autoIncrement:false;index" json:"role_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 指定表名
+func (UserRole) TableName() string {
+	return "user_roles"
+}
+
+// UserRoleRequest 授予角色请求
+type UserRoleRequest struct {
+	Role string `json:"role" binding:"required,max=50"` // 角色编码
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Session 登录会话（存储在 Redis 中，每次登录创建一个）
+type Session struct {
+	ID             string    `json:"id"`                        // 会话ID（对应 JWT 的 sid 声明）
+	UserID         string    `json:"user_id"`                   // 用户ID
+	Device         string    `json:"device"`                    // 设备名称
+	IP             string    `json:"ip"`                        // 登录IP
+	UserAgent      string    `json:"user_agent"`                // 客户端 User-Agent
+	ImpersonatorID string    `json:"impersonator_id,omitempty"` // 代登录的管理员ID，为空表示用户本人登录
+	AccessTokenID  string    `json:"-"`                         // 当前访问令牌的 jti
+	RefreshTokenID string    `json:"-"`                         // 当前刷新令牌的 jti
+	CreatedAt      time.Time `json:"created_at"`                // 登录时间
+	LastSeenAt     time.Time `json:"last_seen_at"`              // 最后活跃时间
+	Current        bool      `json:"current"`                   // 是否为当前请求所在的会话
+}
+
+// ClientInfo 发起请求的客户端信息
+type ClientInfo struct {
+	IP        string // 客户端IP
+	UserAgent string // 客户端 User-Agent
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// UserTwoFactor 用户两步验证（TOTP）配置
+type UserTwoFactor struct {
+	UserID        int64      `gorm:"primarykey;autoIncrement:false" json:"user_id"`
+	Secret        string     `gorm:"type:varchar(255);not null" json:"-"`                         // AES-GCM 加密后的 TOTP 密钥
+	Enabled       int        `gorm:"type:tinyint;default:0;comment:'1-已启用 0-待确认'" json:"enabled"` // 是否已启用
+	RecoveryCodes string     `gorm:"type:text" json:"-"`                                          // 恢复码的 SHA-256 摘要（JSON 数组）
+	LastUsedStep  int64      `gorm:"default:0" json:"-"`                                          // 最近一次使用的时间步，防止验证码重放
+	EnabledAt     *time.Time `json:"enabled_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// 指定表名
+func (UserTwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// TwoFactorCodeRequest 两步验证码请求（TOTP 验证码或恢复码）
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,max=32"` // 认证器中的6位验证码，或恢复码
+}
+
+// TwoFactorEnrollResponse 开启两步验证响应
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret"`      // Base32 密钥，可手动输入认证器
+	OtpauthURL string `json:"otpauth_url"` // otpauth:// 地址，可生成二维码扫描
+}
+
+// TwoFactorRecoveryCodesResponse 恢复码响应（仅生成时返回一次）
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// LoginTwoFactorRequest 登录二次验证请求
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"` // 登录接口返回的挑战令牌
+	Code           string `json:"code" binding:"required,max=32"`     // 6位验证码或恢复码
+}
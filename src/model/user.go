@@ -8,17 +8,18 @@ import (
 
 // User 用户模型
 type User struct {
-	ID        int64          `gorm:"primarykey;autoIncrement:false" json:"id"` // 使用雪花ID，禁用自增
-	Username  string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"username"`
-	Password  string         `gorm:"type:varchar(255);not null" json:"-"` // json:"-" 表示不返回密码
-	Email     string         `gorm:"type:varchar(100);uniqueIndex" json:"email"`
-	Phone     string         `gorm:"type:varchar(20)" json:"phone"`
-	Nickname  string         `gorm:"type:varchar(50)" json:"nickname"`
-	Avatar    string         `gorm:"type:varchar(255)" json:"avatar"`
-	Status    int            `gorm:"type:tinyint;default:1;comment:'1-正常 0-禁用'" json:"status"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID              int64          `gorm:"primarykey;autoIncrement:false" json:"id"` // 使用雪花ID，禁用自增
+	Username        string         `gorm:"type:varchar(50);uniqueIndex;not null" json:"username"`
+	Password        string         `gorm:"type:varchar(255);not null" json:"-"` // json:"-" 表示不返回密码
+	Email           string         `gorm:"type:varchar(100);uniqueIndex" json:"email"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at"` // 邮箱验证时间，为空表示未验证
+	Phone           string         `gorm:"type:varchar(20)" json:"phone"`
+	Nickname        string         `gorm:"type:varchar(50)" json:"nickname"`
+	Avatar          string         `gorm:"type:varchar(255)" json:"avatar"`
+	Status          int            `gorm:"type:tinyint;default:1;comment:'1-正常 0-禁用'" json:"status"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // 指定表名
@@ -30,21 +31,110 @@ func (User) TableName() string {
 type LoginRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"` // 必填，长度3-50
 	Password string `json:"password" binding:"required,min=6"`        // 必填，最小6位
+	Device   string `json:"device" binding:"omitempty,max=50"`        // 设备名称（可选），如 iPhone 15、Chrome
 }
 
 // LoginDataResponse 登录响应数据字段
+// 开启两步验证的用户登录时只返回 two_factor_required 和 challenge_token，
+// 需再调用 /user/login/2fa 换取令牌
 type LoginDataResponse struct {
-	User         *User  `json:"user"`
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	ExpiresIn    int64  `json:"expires_in"` // 过期时间（秒）
+	User              *User  `json:"user,omitempty"`
+	SessionID         string `json:"session_id,omitempty"` // 会话ID
+	AccessToken       string `json:"access_token,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+	ExpiresIn         int64  `json:"expires_in,omitempty"`          // 过期时间（秒）
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"` // 是否需要两步验证
+	ChallengeToken    string `json:"challenge_token,omitempty"`     // 两步验证挑战令牌
+}
+
+// RefreshTokenRequest 刷新令牌请求
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"` // 登录时返回的刷新令牌
 }
 
 // 注册请求
 type RegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50"`
-	Password string `json:"password" binding:"required,min=6,max=20"`
+	Password string `json:"password" binding:"required,max=128"` // 长度、字符种类等由密码策略校验
 	Email    string `json:"email" binding:"omitempty,email"`
 	Phone    string `json:"phone" binding:"omitempty,len=11"`
 	Nickname string `json:"nickname" binding:"omitempty,max=50"`
 }
+
+// ForgotPasswordRequest 忘记密码请求
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"` // 注册邮箱
+}
+
+// ResetPasswordByTokenRequest 通过邮件令牌重置密码请求
+type ResetPasswordByTokenRequest struct {
+	Token    string `json:"token" binding:"required"`            // 邮件中的重置令牌
+	Password string `json:"password" binding:"required,max=128"` // 新密码，由密码策略校验
+}
+
+// VerifyEmailRequest 验证邮箱请求
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"` // 邮件中的验证令牌
+}
+
+// UpdateProfileRequest 修改个人资料请求，未传的字段保持不变
+type UpdateProfileRequest struct {
+	Nickname *string `json:"nickname" binding:"omitempty,max=50"`     // 昵称
+	Phone    *string `json:"phone" binding:"omitempty,max=20"`        // 手机号（11位，传空字符串表示清除）
+	Email    *string `json:"email" binding:"omitempty,email,max=100"` // 邮箱，修改后需要重新验证，不能清空
+	Avatar   *string `json:"avatar" binding:"omitempty,max=255"`      // 头像地址
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`         // 原密码
+	NewPassword string `json:"new_password" binding:"required,max=128"` // 新密码，由密码策略校验
+}
+
+// DeleteAccountRequest 注销账号请求
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`     // 当前密码
+	Code     string `json:"code" binding:"omitempty,max=20"` // 两步验证码或恢复码（已开启两步验证时必填）
+}
+
+// UserListRequest 用户列表请求（管理员）
+type UserListRequest struct {
+	Keyword  string `form:"keyword" binding:"omitempty,max=50"`   // 搜索关键词（用户名、昵称、邮箱、手机号）
+	Status   *int   `form:"status" binding:"omitempty,oneof=0 1"` // 状态：1-正常 0-禁用
+	Deleted  bool   `form:"deleted"`                              // 是否只查询已删除的用户
+	Page     int    `form:"page" binding:"omitempty,min=1"`       // 页码
+	PageSize int    `form:"page_size" binding:"omitempty,min=1"`  // 每页数量
+}
+
+// UserListDataResponse 用户列表data字段
+type UserListDataResponse struct {
+	List  []User `json:"list"`  // 列表数据
+	Total int64  `json:"total"` // 总数
+}
+
+// UserDetailResponse 用户详情（管理员）
+type UserDetailResponse struct {
+	User      *User      `json:"user"`                 // 用户信息
+	Roles     []string   `json:"roles"`                // 角色编码
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // 删除时间（已删除用户）
+}
+
+// UserStatusRequest 修改用户状态请求
+type UserStatusRequest struct {
+	Status *int `json:"status" binding:"required,oneof=0 1"` // 状态：1-正常 0-禁用
+}
+
+// ResetPasswordRequest 管理员重置密码请求
+type ResetPasswordRequest struct {
+	Password string `json:"password" binding:"required,max=128"` // 新密码，由密码策略校验
+}
+
+// ImpersonateRequest 管理员代登录请求
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"` // 代登录原因（如工单号），记录在审计日志中
+}
+
+// UserRolesRequest 设置用户角色请求
+type UserRolesRequest struct {
+	Roles []string `json:"roles" binding:"required,dive,max=50"` // 角色编码列表，覆盖原有角色
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// UserIdentity 用户绑定的第三方身份（OAuth2 / OIDC）
+// 同一身份提供方下，subject 唯一确定一个外部账号
+type UserIdentity struct {
+	ID        int64     `gorm:"primarykey;autoIncrement:false" json:"id"`
+	UserID    int64     `gorm:"index;not null" json:"user_id"`
+	Provider  string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_provider_subject" json:"provider"` // 身份提供方名称（配置中的键）
+	Subject   string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_provider_subject" json:"-"`       // ID Token 中的 sub
+	Email     string    `gorm:"type:varchar(100)" json:"email"`                                             // 身份提供方返回的邮箱
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 指定表名
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
+
+// OAuthCallbackRequest 第三方登录回调参数
+type OAuthCallbackRequest struct {
+	Code             string `form:"code"`              // 授权码
+	State            string `form:"state"`             // 发起登录时生成的 state
+	Error            string `form:"error"`             // 用户拒绝授权等错误码
+	ErrorDescription string `form:"error_description"` // 错误描述
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/utils/mysql"
+	"time"
+)
+
+// APIKeyRepository API Key 数据访问层
+type APIKeyRepository struct{}
+
+// NewAPIKeyRepository 创建 API Key 仓储实例
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{}
+}
+
+// Create 创建 API Key
+func (r *APIKeyRepository) Create(key *model.APIKey) error {
+	return mysql.Database.Create(key).Error
+}
+
+// FindByHash 根据 Key 摘要查找
+func (r *APIKeyRepository) FindByHash(hash string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := mysql.Database.Where("key_hash = ?", hash).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// ListByUserID 获取用户的全部 API Key
+func (r *APIKeyRepository) ListByUserID(userID int64) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	err := mysql.Database.Where("user_id = ?", userID).Order("id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// CountByUserID 统计用户的 API Key 数量
+func (r *APIKeyRepository) CountByUserID(userID int64) (int64, error) {
+	var count int64
+	err := mysql.Database.Model(&model.APIKey{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// Delete 删除用户的指定 API Key，返回是否删除成功
+func (r *APIKeyRepository) Delete(userID, id int64) (bool, error) {
+	result := mysql.Database.Where("id = ? AND user_id = ?", id, userID).Delete(&model.APIKey{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteByUserID 删除用户的全部 API Key
+func (r *APIKeyRepository) DeleteByUserID(userID int64) error {
+	return mysql.Database.Where("user_id = ?", userID).Delete(&model.APIKey{}).Error
+}
+
+// UpdateLastUsed 记录最近一次使用时间和IP
+func (r *APIKeyRepository) UpdateLastUsed(id int64, t time.Time, ip string) error {
+	return mysql.Database.Model(&model.APIKey{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": t, "last_used_ip": ip}).Error
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/utils/mysql"
+	"strings"
+	"time"
+)
+
+// AuditLogRepository 审计日志数据访问层
+type AuditLogRepository struct{}
+
+// NewAuditLogRepository 创建审计日志仓储实例
+func NewAuditLogRepository() *AuditLogRepository {
+	return &AuditLogRepository{}
+}
+
+// Create 写入审计日志
+func (r *AuditLogRepository) Create(log *model.AuditLog) error {
+	return mysql.Database.Create(log).Error
+}
+
+// List 按操作人、操作类型和时间范围分页查询审计日志（按时间倒序）
+// action 以 . 结尾时按前缀匹配
+func (r *AuditLogRepository) List(actorID int64, action string, start, end *time.Time, page, pageSize int) ([]model.AuditLog, int64, error) {
+	var logs []model.AuditLog
+	var total int64
+
+	// 构建查询
+	query := mysql.Database.Model(&model.AuditLog{})
+	if actorID != 0 {
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if action != "" {
+		if strings.HasSuffix(action, ".") {
+			query = query.Where("action LIKE ?", action+"%")
+		} else {
+			query = query.Where("action = ?", action)
+		}
+	}
+	if start != nil {
+		query = query.Where("created_at >= ?", *start)
+	}
+	if end != nil {
+		query = query.Where("created_at < ?", *end)
+	}
+
+	// 查询总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("created_at DESC, id DESC").Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return logs, total, nil
+}
@@ -1,10 +1,27 @@
 package repository
 
 import (
+	"database/sql"
+	"errors"
 	"hi-go/src/model"
 	"hi-go/src/utils/mysql"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// errReorderConflict 调整排序时版本号不匹配，用于回滚事务
+var errReorderConflict = errors.New("reorder version conflict")
+
+// HomeFilter 首页内容筛选条件
+type HomeFilter struct {
+	SlotID    int64  // 版位ID，0 表示不限
+	Tag       string // 标签名称，为空表示不限
+	MaxItems  int    // 最多返回的条数（版位容量），0 表示不限
+	SortOrder string // 排序方式，见 model.HomeSlotSort*，为空时按排序值
+}
+
 // HomeRepository 首页数据访问层
 type HomeRepository struct{}
 
@@ -13,24 +30,33 @@ func NewHomeRepository() *HomeRepository {
 	return &HomeRepository{}
 }
 
-// List 获取首页列表（分页）
-func (r *HomeRepository) List(page, pageSize int) ([]model.Home, int64, error) {
+// List 获取 at 时刻处于发布期内的首页列表（分页）
+// includeUpcoming 为 true 时包含尚未到发布时间的内容，用于预览；filter.MaxItems 大于 0 时只返回排在前面的 MaxItems 条
+func (r *HomeRepository) List(at time.Time, includeUpcoming bool, filter HomeFilter, page, pageSize int) ([]model.Home, int64, error) {
 	var homes []model.Home
 	var total int64
 
 	// 构建查询
 	query := mysql.Database.Model(&model.Home{}).
-		Where("status = ?", 1).    // 只查询启用的
-		Order("sort ASC, id DESC") // 按排序和ID倒序
+		Where("status = ?", 1). // 只查询启用的
+		Scopes(publishedAt(at, includeUpcoming), filterBy(filter)).
+		Order(homeOrder(filter.SortOrder))
 
 	// 查询总数
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// 分页查询
-	offset := (page - 1) * pageSize
-	if err := query.Offset(offset).Limit(pageSize).Find(&homes).Error; err != nil {
+	// 分页查询，超出版位容量的部分不返回
+	offset, limit := (page-1)*pageSize, pageSize
+	if filter.MaxItems > 0 {
+		total = min(total, int64(filter.MaxItems))
+		limit = min(limit, filter.MaxItems-offset)
+		if limit <= 0 {
+			return []model.Home{}, total, nil
+		}
+	}
+	if err := query.Preload("Tags").Offset(offset).Limit(limit).Find(&homes).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -40,7 +66,20 @@ func (r *HomeRepository) List(page, pageSize int) ([]model.Home, int64, error) {
 // 根据ID查找首页内容
 func (r *HomeRepository) FindByID(id int64) (*model.Home, error) {
 	var home model.Home
-	err := mysql.Database.First(&home, id).Error
+	err := mysql.Database.Preload("Tags").First(&home, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &home, nil
+}
+
+// FindPublishedByID 根据ID查询 at 时刻对外展示的首页内容（已启用且处于发布期内）
+func (r *HomeRepository) FindPublishedByID(id int64, at time.Time) (*model.Home, error) {
+	var home model.Home
+	err := mysql.Database.Preload("Tags").
+		Where("status = ?", 1).
+		Scopes(publishedAt(at, false)).
+		First(&home, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -57,18 +96,204 @@ func (r *HomeRepository) BatchCreate(homes []model.Home) error {
 	return mysql.Database.Create(&homes).Error
 }
 
-// Update 更新首页内容
-func (r *HomeRepository) Update(id int64, updates map[string]interface{}) error {
-	return mysql.Database.Model(&model.Home{}).Where("id = ?", id).Updates(updates).Error
+// ImportBatch 批量导入首页内容，每批 batchSize 条
+// gorm 的 CreateInBatches 会把全部批次放在同一个事务中执行，任一批失败则全部回滚
+func (r *HomeRepository) ImportBatch(homes []model.Home, batchSize int) error {
+	return mysql.CreateBatch(&homes, batchSize)
+}
+
+// ExportEach 按ID顺序分批读取符合条件的首页内容，每批调用一次 fn，fn 返回错误时停止
+// keyword 为空表示不按关键词筛选，status 为 nil 表示全部状态
+func (r *HomeRepository) ExportEach(keyword string, status *int, batchSize int, fn func([]model.Home) error) error {
+	query := mysql.Database.Model(&model.Home{})
+	if keyword != "" {
+		keyword = "%" + keyword + "%"
+		query = query.Where("title LIKE ? OR description LIKE ?", keyword, keyword)
+	}
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	var homes []model.Home
+	return query.Preload("Tags").FindInBatches(&homes, batchSize, func(tx *gorm.DB, batch int) error {
+		return fn(homes)
+	}).Error
 }
 
-// Delete 删除首页内容
+// Update 更新首页内容，同时递增版本号，返回修改后的内容
+// tags 不为 nil 时在同一事务中用这些标签覆盖原有标签（不存在的标签自动创建），空切片表示清空；
+// revision 不为 nil 时在同一事务中保存修订记录，调用方只需填写修改人、修订类型等，快照和版本号自动生成
+func (r *HomeRepository) Update(id int64, updates map[string]interface{}, tags []string, revision *model.HomeRevision) (*model.Home, error) {
+	values := make(map[string]interface{}, len(updates)+1)
+	for k, v := range updates {
+		values[k] = v
+	}
+	values["version"] = gorm.Expr("version + 1")
+
+	var home model.Home
+	err := mysql.Transaction(func(tx *gorm.DB) error {
+		// 1. 锁定并读取修改前的内容
+		var before model.Home
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Tags").First(&before, id).Error; err != nil {
+			return err
+		}
+
+		// 2. 更新字段和标签
+		if err := tx.Model(&model.Home{}).Where("id = ?", id).Updates(values).Error; err != nil {
+			return err
+		}
+		if tags != nil {
+			homeTags, err := findOrCreateTags(tx, tags)
+			if err != nil {
+				return err
+			}
+			if err := tx.Model(&model.Home{ID: id}).Association("Tags").Replace(homeTags); err != nil {
+				return err
+			}
+		}
+
+		// 3. 读取修改后的内容并保存修订记录
+		if err := tx.Preload("Tags").First(&home, id).Error; err != nil {
+			return err
+		}
+		if revision == nil {
+			return nil
+		}
+		return saveRevision(tx, &before, &home, *revision)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &home, nil
+}
+
+// FindByIDs 根据ID批量查找首页内容
+func (r *HomeRepository) FindByIDs(ids []int64) ([]model.Home, error) {
+	var homes []model.Home
+	err := mysql.Database.Preload("Tags").Where("id IN ?", ids).Find(&homes).Error
+	return homes, err
+}
+
+// Reorder 在同一事务中更新排序（乐观锁）
+// 每条按 items 中的版本号条件更新并递增版本号，任一条版本号不匹配时回滚全部修改，返回版本号不匹配的ID
+func (r *HomeRepository) Reorder(items []model.HomeSortVersion) ([]int64, error) {
+	var conflicts []int64
+	err := mysql.Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			result := tx.Model(&model.Home{}).
+				Where("id = ? AND version = ?", item.ID, item.Version).
+				Updates(map[string]interface{}{
+					"sort":    item.Sort,
+					"version": gorm.Expr("version + 1"),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				conflicts = append(conflicts, item.ID)
+			}
+		}
+		if len(conflicts) > 0 {
+			return errReorderConflict
+		}
+		return nil
+	})
+	if errors.Is(err, errReorderConflict) {
+		return conflicts, nil
+	}
+	return nil, err
+}
+
+// Delete 删除首页内容（软删除，移入回收站）
 func (r *HomeRepository) Delete(id int64) error {
 	return mysql.Database.Delete(&model.Home{}, id).Error
 }
 
-// Search 搜索首页内容（标题和描述模糊搜索）
-func (r *HomeRepository) Search(keyword string, page, pageSize int) ([]model.Home, int64, error) {
+// ListDeleted 获取回收站中的首页内容（分页，最近删除的在前）
+func (r *HomeRepository) ListDeleted(filter HomeFilter, page, pageSize int) ([]model.Home, int64, error) {
+	var homes []model.Home
+	var total int64
+
+	query := mysql.Database.Unscoped().Model(&model.Home{}).
+		Where("deleted_at IS NOT NULL").
+		Scopes(filterBy(filter))
+
+	// 查询总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	if err := query.Preload("Tags").Order("deleted_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&homes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return homes, total, nil
+}
+
+// FindDeletedByID 根据ID查找回收站中的首页内容
+func (r *HomeRepository) FindDeletedByID(id int64) (*model.Home, error) {
+	var home model.Home
+	err := mysql.Database.Unscoped().Where("deleted_at IS NOT NULL").First(&home, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &home, nil
+}
+
+// Restore 从回收站恢复首页内容
+func (r *HomeRepository) Restore(id int64) error {
+	return mysql.Database.Unscoped().Model(&model.Home{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
+// Purge 彻底删除回收站中的首页内容
+func (r *HomeRepository) Purge(id int64) error {
+	return mysql.Transaction(func(tx *gorm.DB) error {
+		_, err := purgeHomes(tx, []int64{id})
+		return err
+	})
+}
+
+// PurgeDeletedBefore 彻底删除在 before 之前移入回收站的首页内容，单次最多删除 limit 条，返回删除数量
+func (r *HomeRepository) PurgeDeletedBefore(before time.Time, limit int) (int64, error) {
+	var ids []int64
+	err := mysql.Database.Unscoped().Model(&model.Home{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+
+	var purged int64
+	err = mysql.Transaction(func(tx *gorm.DB) error {
+		purged, err = purgeHomes(tx, ids)
+		return err
+	})
+	return purged, err
+}
+
+// purgeHomes 彻底删除回收站中的指定内容及其修订记录，先删除标签关联（关联表有外键约束）
+func purgeHomes(tx *gorm.DB, ids []int64) (int64, error) {
+	homes := make([]model.Home, len(ids))
+	for i, id := range ids {
+		homes[i].ID = id
+	}
+	if err := tx.Model(&homes).Association("Tags").Clear(); err != nil {
+		return 0, err
+	}
+	if err := tx.Where("home_id IN ?", ids).Delete(&model.HomeRevision{}).Error; err != nil {
+		return 0, err
+	}
+	result := tx.Unscoped().Where("deleted_at IS NOT NULL").Delete(&model.Home{}, ids)
+	return result.RowsAffected, result.Error
+}
+
+// Search 搜索 at 时刻处于发布期内的首页内容（标题和描述模糊搜索）
+func (r *HomeRepository) Search(keyword string, at time.Time, filter HomeFilter, page, pageSize int) ([]model.Home, int64, error) {
 	var homes []model.Home
 	var total int64
 
@@ -81,8 +306,8 @@ func (r *HomeRepository) Search(keyword string, page, pageSize int) ([]model.Hom
 		query = query.Where("title LIKE ? OR description LIKE ?", keyword, keyword)
 	}
 
-	// 只查询启用的
-	query = query.Where("status = ?", 1)
+	// 只查询启用且处于发布期内的
+	query = query.Where("status = ?", 1).Scopes(publishedAt(at, false), filterBy(filter))
 
 	// 查询总数
 	if err := query.Count(&total).Error; err != nil {
@@ -91,9 +316,80 @@ func (r *HomeRepository) Search(keyword string, page, pageSize int) ([]model.Hom
 
 	// 分页查询
 	offset := (page - 1) * pageSize
-	if err := query.Offset(offset).Limit(pageSize).Order("sort ASC, id DESC").Find(&homes).Error; err != nil {
+	if err := query.Preload("Tags").Offset(offset).Limit(pageSize).Order(homeOrder(filter.SortOrder)).Find(&homes).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return homes, total, nil
 }
+
+// NextWindowChange 获取 after 之后最近一次发布期开始或结束的时间，没有待生效的发布期时返回 nil
+func (r *HomeRepository) NextWindowChange(after time.Time) (*time.Time, error) {
+	var next *time.Time
+	for _, column := range []string{"publish_at", "unpublish_at"} {
+		var t sql.NullTime
+		err := mysql.Database.Model(&model.Home{}).
+			Select("MIN("+column+")").
+			Where("status = ? AND "+column+" > ?", 1, after).
+			Scan(&t).Error
+		if err != nil {
+			return nil, err
+		}
+		if t.Valid && (next == nil || t.Time.Before(*next)) {
+			next = &t.Time
+		}
+	}
+	return next, nil
+}
+
+// publishedAt 筛选 at 时刻处于发布期内的内容，未设置的时间表示不限制
+func publishedAt(at time.Time, includeUpcoming bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !includeUpcoming {
+			db = db.Where("publish_at IS NULL OR publish_at <= ?", at)
+		}
+		return db.Where("unpublish_at IS NULL OR unpublish_at > ?", at)
+	}
+}
+
+// filterBy 按版位和标签筛选
+func filterBy(filter HomeFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.SlotID > 0 {
+			db = db.Where("slot_id = ?", filter.SlotID)
+		}
+		if filter.Tag != "" {
+			db = db.Where("id IN (?)", mysql.Database.Table("home_tag_relations").
+				Select("home_tag_relations.home_id").
+				Joins("JOIN home_tags ON home_tags.id = home_tag_relations.home_tag_id").
+				Where("home_tags.name = ?", filter.Tag))
+		}
+		return db
+	}
+}
+
+// homeOrder 排序方式对应的 ORDER BY
+func homeOrder(sortOrder string) string {
+	if sortOrder == model.HomeSlotSortNewest {
+		return "COALESCE(publish_at, created_at) DESC, id DESC"
+	}
+	return "sort ASC, id DESC" // 按排序和ID倒序
+}
+
+// findOrCreateTags 按名称查找标签，不存在的自动创建
+func findOrCreateTags(tx *gorm.DB, names []string) ([]model.HomeTag, error) {
+	tags := []model.HomeTag{}
+	if len(names) == 0 {
+		return tags, nil
+	}
+
+	newTags := make([]model.HomeTag, 0, len(names))
+	for _, name := range names {
+		newTags = append(newTags, model.HomeTag{Name: name})
+	}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&newTags).Error; err != nil {
+		return nil, err
+	}
+	err := tx.Where("name IN ?", names).Find(&tags).Error
+	return tags, err
+}
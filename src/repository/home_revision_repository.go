@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/utils/mysql"
+
+	"gorm.io/gorm"
+)
+
+// HomeRevisionRepository 首页内容修订记录数据访问层
+// 修订记录在 HomeRepository.Update 的事务中写入
+type HomeRevisionRepository struct{}
+
+// NewHomeRevisionRepository 创建修订记录仓储实例
+func NewHomeRevisionRepository() *HomeRevisionRepository {
+	return &HomeRevisionRepository{}
+}
+
+// List 获取首页内容的修订记录（分页，最新的在前）
+func (r *HomeRevisionRepository) List(homeID int64, page, pageSize int) ([]model.HomeRevision, int64, error) {
+	var revisions []model.HomeRevision
+	var total int64
+
+	query := mysql.Database.Model(&model.HomeRevision{}).Where("home_id = ?", homeID)
+
+	// 查询总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	if err := query.Order("version DESC").Offset(offset).Limit(pageSize).Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, total, nil
+}
+
+// FindByID 根据ID查找首页内容的修订记录
+func (r *HomeRevisionRepository) FindByID(homeID, id int64) (*model.HomeRevision, error) {
+	var revision model.HomeRevision
+	if err := mysql.Database.Where("home_id = ?", homeID).First(&revision, id).Error; err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// saveRevision 在事务中保存修订记录，home 为修改后的内容
+// before 为修改前的内容：该内容还没有修订记录时先保存一份原始内容，以便回滚到第一次修改之前
+func saveRevision(tx *gorm.DB, before, home *model.Home, revision model.HomeRevision) error {
+	var count int64
+	if err := tx.Model(&model.HomeRevision{}).Where("home_id = ?", home.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		initial := model.HomeRevision{
+			HomeID:   before.ID,
+			Version:  before.Version,
+			Action:   model.HomeRevisionInitial,
+			Snapshot: model.NewHomeSnapshot(before),
+		}
+		if err := tx.Create(&initial).Error; err != nil {
+			return err
+		}
+	}
+
+	revision.HomeID = home.ID
+	revision.Version = home.Version
+	revision.Snapshot = model.NewHomeSnapshot(home)
+	return tx.Create(&revision).Error
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/utils/mysql"
+
+	"gorm.io/gorm"
+)
+
+// HomeSlotRepository 首页版位数据访问层
+type HomeSlotRepository struct{}
+
+// NewHomeSlotRepository 创建首页版位仓储实例
+func NewHomeSlotRepository() *HomeSlotRepository {
+	return &HomeSlotRepository{}
+}
+
+// FindAll 获取全部版位
+func (r *HomeSlotRepository) FindAll() ([]model.HomeSlot, error) {
+	var slots []model.HomeSlot
+	err := mysql.Database.Order("id ASC").Find(&slots).Error
+	return slots, err
+}
+
+// FindByID 根据ID查找版位
+func (r *HomeSlotRepository) FindByID(id int64) (*model.HomeSlot, error) {
+	var slot model.HomeSlot
+	if err := mysql.Database.First(&slot, id).Error; err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+// FindByCode 根据编码查找版位
+func (r *HomeSlotRepository) FindByCode(code string) (*model.HomeSlot, error) {
+	var slot model.HomeSlot
+	if err := mysql.Database.Where("code = ?", code).First(&slot).Error; err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
+
+// ExistsByCode 编码是否已存在
+func (r *HomeSlotRepository) ExistsByCode(code string) (bool, error) {
+	var count int64
+	err := mysql.Database.Model(&model.HomeSlot{}).Where("code = ?", code).Count(&count).Error
+	return count > 0, err
+}
+
+// Create 创建版位
+func (r *HomeSlotRepository) Create(slot *model.HomeSlot) error {
+	return mysql.Database.Create(slot).Error
+}
+
+// Update 更新版位
+func (r *HomeSlotRepository) Update(id int64, updates map[string]interface{}) error {
+	return mysql.Database.Model(&model.HomeSlot{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// Delete 删除版位，并将版位下的内容（含回收站中的）移出版位，返回被移出的内容ID
+func (r *HomeSlotRepository) Delete(id int64) ([]int64, error) {
+	var ids []int64
+	err := mysql.Transaction(func(tx *gorm.DB) error {
+		query := tx.Unscoped().Model(&model.Home{}).Where("slot_id = ?", id)
+		if err := query.Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) > 0 {
+			err := tx.Unscoped().Model(&model.Home{}).
+				Where("id IN ?", ids).
+				Updates(map[string]interface{}{
+					"slot_id": nil,
+					"version": gorm.Expr("version + 1"),
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&model.HomeSlot{}, id).Error
+	})
+	return ids, err
+}
+
+// CountItems 统计各版位下未删除的内容数量
+func (r *HomeSlotRepository) CountItems() (map[int64]int64, error) {
+	var rows []struct {
+		SlotID int64
+		Count  int64
+	}
+	err := mysql.Database.Model(&model.Home{}).
+		Select("slot_id, COUNT(*) AS count").
+		Where("slot_id IS NOT NULL").
+		Group("slot_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		counts[row.SlotID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/utils/mysql"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HomeStatsRepository 首页内容曝光点击统计数据访问层
+type HomeStatsRepository struct{}
+
+// NewHomeStatsRepository 创建统计仓储实例
+func NewHomeStatsRepository() *HomeStatsRepository {
+	return &HomeStatsRepository{}
+}
+
+// Accumulate 将一批计数累加到每日统计中，当天已有记录时在原有数值上累加
+func (r *HomeStatsRepository) Accumulate(stats []model.HomeDailyStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return mysql.Database.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "home_id"}, {Name: "stat_date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"impressions": gorm.Expr("impressions + VALUES(impressions)"),
+			"clicks":      gorm.Expr("clicks + VALUES(clicks)"),
+			"updated_at":  gorm.Expr("VALUES(updated_at)"),
+		}),
+	}).CreateInBatches(stats, 500).Error
+}
+
+// ExistingHomeIDs 返回 ids 中实际存在的首页内容ID（包括回收站中的内容）
+func (r *HomeStatsRepository) ExistingHomeIDs(ids []int64) ([]int64, error) {
+	var existing []int64
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	err := mysql.Database.Unscoped().Model(&model.Home{}).Where("id IN ?", ids).Pluck("id", &existing).Error
+	return existing, err
+}
+
+// Report 按内容汇总日期范围内的曝光点击数据（分页，按曝光次数倒序）
+// 已删除（在回收站中）的内容仍然统计
+func (r *HomeStatsRepository) Report(start, end time.Time, homeID int64, page, pageSize int) ([]model.HomeStatsItem, int64, error) {
+	var items []model.HomeStatsItem
+	var total int64
+
+	// 汇总查询和计数查询使用相同的条件，分别构建避免互相影响
+	homeTable := model.Home{}.TableName()
+	scope := func() *gorm.DB {
+		query := mysql.Database.Table(model.HomeDailyStat{}.TableName()+" AS s").
+			Joins("JOIN "+homeTable+" AS h ON h.id = s.home_id").
+			Where("s.stat_date BETWEEN ? AND ?", start, end)
+		if homeID > 0 {
+			query = query.Where("s.home_id = ?", homeID)
+		}
+		return query
+	}
+
+	// 查询总数
+	if err := scope().Distinct("s.home_id").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	err := scope().Select("s.home_id, h.title, SUM(s.impressions) AS impressions, SUM(s.clicks) AS clicks").
+		Group("s.home_id, h.title").
+		Order("impressions DESC, s.home_id").
+		Offset(offset).Limit(pageSize).
+		Scan(&items).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
@@ -0,0 +1,91 @@
+package repository_test
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/utils/testutil"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	t, _ := time.ParseInLocation(time.DateOnly, s, time.Local)
+	return t
+}
+
+func TestHomeStatsReport(t *testing.T) {
+	db := testutil.SetupDB(t, &model.Home{}, &model.HomeTag{}, &model.HomeDailyStat{})
+
+	homes := []model.Home{
+		{ID: 1, Title: "轮播一", Status: 1},
+		{ID: 2, Title: "轮播二", Status: 1},
+		{ID: 3, Title: "已删除", Status: 1},
+	}
+	if err := db.Create(&homes).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(&model.Home{}, 3).Error; err != nil {
+		t.Fatal(err)
+	}
+	stats := []model.HomeDailyStat{
+		{HomeID: 1, StatDate: day("2026-10-01"), Impressions: 100, Clicks: 5},
+		{HomeID: 1, StatDate: day("2026-10-02"), Impressions: 100, Clicks: 15},
+		{HomeID: 2, StatDate: day("2026-10-01"), Impressions: 50, Clicks: 10},
+		{HomeID: 3, StatDate: day("2026-10-02"), Impressions: 10, Clicks: 1},
+		{HomeID: 2, StatDate: day("2026-10-05"), Impressions: 999, Clicks: 999}, // 范围外
+		{HomeID: 99, StatDate: day("2026-10-01"), Impressions: 7, Clicks: 7},    // 内容不存在
+	}
+	if err := db.Create(&stats).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	repo := repository.NewHomeStatsRepository()
+
+	t.Run("全部内容", func(t *testing.T) {
+		items, total, err := repo.Report(day("2026-10-01"), day("2026-10-02"), 0, 1, 20)
+		if err != nil {
+			t.Fatalf("Report 失败: %v", err)
+		}
+		if total != 3 || len(items) != 3 {
+			t.Fatalf("total=%d len=%d，期望 3", total, len(items))
+		}
+		want := []model.HomeStatsItem{
+			{HomeID: 1, Title: "轮播一", Impressions: 200, Clicks: 20},
+			{HomeID: 2, Title: "轮播二", Impressions: 50, Clicks: 10},
+			{HomeID: 3, Title: "已删除", Impressions: 10, Clicks: 1},
+		}
+		for i := range want {
+			if items[i] != want[i] {
+				t.Errorf("第 %d 行 = %+v，期望 %+v", i, items[i], want[i])
+			}
+		}
+	})
+
+	t.Run("指定内容和分页", func(t *testing.T) {
+		items, total, err := repo.Report(day("2026-10-01"), day("2026-10-31"), 2, 1, 20)
+		if err != nil {
+			t.Fatalf("Report 失败: %v", err)
+		}
+		if total != 1 || len(items) != 1 || items[0].Impressions != 1049 {
+			t.Fatalf("total=%d items=%+v", total, items)
+		}
+
+		items, total, err = repo.Report(day("2026-10-01"), day("2026-10-02"), 0, 2, 2)
+		if err != nil {
+			t.Fatalf("Report 失败: %v", err)
+		}
+		if total != 3 || len(items) != 1 || items[0].HomeID != 3 {
+			t.Fatalf("第二页 total=%d items=%+v", total, items)
+		}
+	})
+
+	t.Run("已存在的内容ID", func(t *testing.T) {
+		ids, err := repo.ExistingHomeIDs([]int64{1, 3, 99})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 2 {
+			t.Fatalf("ExistingHomeIDs = %v，期望 [1 3]", ids)
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/utils/mysql"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RoleRepository 角色权限数据访问层
+type RoleRepository struct{}
+
+// NewRoleRepository 创建角色仓储实例
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{}
+}
+
+// FindAll 获取全部角色（包含权限）
+func (r *RoleRepository) FindAll() ([]model.Role, error) {
+	var roles []model.Role
+	err := mysql.Database.Preload("Permissions").Order("id ASC").Find(&roles).Error
+	return roles, err
+}
+
+// FindByCode 根据编码查找角色
+func (r *RoleRepository) FindByCode(code string) (*model.Role, error) {
+	var role model.Role
+	err := mysql.Database.Where("code = ?", code).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindCodesByUserID 获取用户拥有的角色编码
+func (r *RoleRepository) FindCodesByUserID(userID int64) ([]string, error) {
+	var codes []string
+	err := mysql.Database.Model(&model.Role{}).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.id ASC").
+		Pluck("roles.code", &codes).Error
+	return codes, err
+}
+
+// FindPermissionCodes 获取若干角色拥有的权限编码（去重）
+func (r *RoleRepository) FindPermissionCodes(roleCodes []string) ([]string, error) {
+	var codes []string
+	if len(roleCodes) == 0 {
+		return codes, nil
+	}
+	err := mysql.Database.Model(&model.Permission{}).
+		Distinct("permissions.code").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Where("roles.code IN ?", roleCodes).
+		Pluck("permissions.code", &codes).Error
+	return codes, err
+}
+
+// EnsurePermission 权限不存在时创建
+func (r *RoleRepository) EnsurePermission(permission *model.Permission) error {
+	return mysql.Database.Where("code = ?", permission.Code).FirstOrCreate(permission).Error
+}
+
+// EnsureRole 角色不存在时创建，并补齐给定的权限
+func (r *RoleRepository) EnsureRole(role *model.Role, permissions []model.Permission) error {
+	if err := mysql.Database.Where("code = ?", role.Code).
+		Omit("Permissions").FirstOrCreate(role).Error; err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+	return mysql.Database.Model(role).Association("Permissions").Append(permissions)
+}
+
+// AssignRole 为用户授予角色（已拥有时忽略）
+func (r *RoleRepository) AssignRole(userID, roleID int64) error {
+	return mysql.Database.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.UserRole{UserID: userID, RoleID: roleID}).Error
+}
+
+// FindByCodes 根据编码批量查找角色
+func (r *RoleRepository) FindByCodes(codes []string) ([]model.Role, error) {
+	var roles []model.Role
+	if len(codes) == 0 {
+		return roles, nil
+	}
+	err := mysql.Database.Where("code IN ?", codes).Find(&roles).Error
+	return roles, err
+}
+
+// ReplaceRoles 用给定角色覆盖用户原有的全部角色
+func (r *RoleRepository) ReplaceRoles(userID int64, roleIDs []int64) error {
+	return mysql.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+		userRoles := make([]model.UserRole, 0, len(roleIDs))
+		for _, roleID := range roleIDs {
+			userRoles = append(userRoles, model.UserRole{UserID: userID, RoleID: roleID})
+		}
+		return tx.Create(&userRoles).Error
+	})
+}
+
+// RemoveRole 撤销用户的角色
+func (r *RoleRepository) RemoveRole(userID, roleID int64) error {
+	return mysql.Database.Where("user_id = ? AND role_id = ?", userID, roleID).
+		Delete(&model.UserRole{}).Error
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hi-go/src/model"
+	"hi-go/src/utils/redis"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// ErrSessionTokenMismatch 会话当前的刷新令牌与请求中的不一致（已被轮换或并发使用）
+var ErrSessionTokenMismatch = errors.New("会话令牌不匹配")
+
+// SessionRepository 登录会话数据访问层（Redis）
+// 每个会话保存为一个 Hash：jwt:session:{userID}:{sessionID}
+// 用户的全部会话ID保存在有序集合 jwt:sessions:{userID} 中，分数为创建时间
+type SessionRepository struct{}
+
+// NewSessionRepository 创建会话仓储实例
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{}
+}
+
+// sessionKey 会话 Hash 的键
+func sessionKey(userID, sessionID string) string {
+	return fmt.Sprintf("jwt:session:%s:%s", userID, sessionID)
+}
+
+// sessionIndexKey 用户会话索引的键
+func sessionIndexKey(userID string) string {
+	return fmt.Sprintf("jwt:sessions:%s", userID)
+}
+
+// Create 保存新会话并加入用户会话索引
+func (r *SessionRepository) Create(ctx context.Context, session *model.Session, ttl time.Duration) error {
+	return r.create(ctx, session, ttl, ttl)
+}
+
+// CreateTemporary 保存短期会话（如管理员代登录）并加入用户会话索引
+// 会话在 ttl 后过期，索引的过期时间使用 indexTTL，避免缩短用户其他会话的索引有效期
+func (r *SessionRepository) CreateTemporary(ctx context.Context, session *model.Session, ttl, indexTTL time.Duration) error {
+	return r.create(ctx, session, ttl, indexTTL)
+}
+
+// create 保存会话 Hash 并加入用户会话索引
+func (r *SessionRepository) create(ctx context.Context, session *model.Session, ttl, indexTTL time.Duration) error {
+	key := sessionKey(session.UserID, session.ID)
+	indexKey := sessionIndexKey(session.UserID)
+	_, err := redis.TxPipeline(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			"id", session.ID,
+			"user_id", session.UserID,
+			"device", session.Device,
+			"ip", session.IP,
+			"user_agent", session.UserAgent,
+			"impersonator_id", session.ImpersonatorID,
+			"access_id", session.AccessTokenID,
+			"refresh_id", session.RefreshTokenID,
+			"created_at", session.CreatedAt.Unix(),
+			"last_seen_at", session.LastSeenAt.Unix(),
+		)
+		pipe.Expire(ctx, key, ttl)
+		pipe.ZAdd(ctx, indexKey, goredis.Z{Score: float64(session.CreatedAt.UnixNano()), Member: session.ID})
+		pipe.Expire(ctx, indexKey, indexTTL)
+		return nil
+	})
+	return err
+}
+
+// Find 查找会话，不存在时返回 redis.ErrKeyNotFound
+func (r *SessionRepository) Find(ctx context.Context, userID, sessionID string) (*model.Session, error) {
+	if userID == "" || sessionID == "" {
+		return nil, redis.ErrKeyNotFound
+	}
+	fields, err := redis.HGetAll(ctx, sessionKey(userID, sessionID))
+	if err != nil {
+		return nil, err
+	}
+	if fields["id"] == "" {
+		return nil, redis.ErrKeyNotFound
+	}
+	return parseSession(fields), nil
+}
+
+// List 列出用户的全部有效会话（按创建时间升序），顺带清理索引中已过期的会话
+func (r *SessionRepository) List(ctx context.Context, userID string) ([]*model.Session, error) {
+	ids, err := redis.ZRange(ctx, sessionIndexKey(userID), 0, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*model.Session, 0, len(ids))
+	for _, id := range ids {
+		session, err := r.Find(ctx, userID, id)
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			redis.ZRem(ctx, sessionIndexKey(userID), id)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+// Rotate 轮换会话令牌
+// 仅当会话当前的刷新令牌为 expectedRefreshID 时才写入新的令牌ID（WATCH 保证同一刷新令牌只能使用一次）
+func (r *SessionRepository) Rotate(ctx context.Context, userID, sessionID, expectedRefreshID, accessID, refreshID string, ttl time.Duration) error {
+	key := sessionKey(userID, sessionID)
+	err := redis.Watch(ctx, func(tx *goredis.Tx) error {
+		current, err := tx.HGet(ctx, key, "refresh_id").Result()
+		if err == goredis.Nil {
+			return redis.ErrKeyNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if current != expectedRefreshID {
+			return ErrSessionTokenMismatch
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.HSet(ctx, key,
+				"access_id", accessID,
+				"refresh_id", refreshID,
+				"last_seen_at", time.Now().Unix(),
+			)
+			pipe.Expire(ctx, key, ttl)
+			pipe.Expire(ctx, sessionIndexKey(userID), ttl)
+			return nil
+		})
+		return err
+	}, key)
+	if errors.Is(err, goredis.TxFailedErr) {
+		return ErrSessionTokenMismatch
+	}
+	return err
+}
+
+// Touch 更新会话最后活跃时间（会话已被删除时不做任何操作）
+func (r *SessionRepository) Touch(ctx context.Context, userID, sessionID string, at time.Time) error {
+	key := sessionKey(userID, sessionID)
+	err := redis.Watch(ctx, func(tx *goredis.Tx) error {
+		n, err := tx.Exists(ctx, key).Result()
+		if err != nil || n == 0 {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.HSet(ctx, key, "last_seen_at", at.Unix())
+			return nil
+		})
+		return err
+	}, key)
+	if errors.Is(err, goredis.TxFailedErr) {
+		return nil
+	}
+	return err
+}
+
+// Delete 删除会话
+func (r *SessionRepository) Delete(ctx context.Context, userID, sessionID string) error {
+	_, err := redis.TxPipeline(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, sessionKey(userID, sessionID))
+		pipe.ZRem(ctx, sessionIndexKey(userID), sessionID)
+		return nil
+	})
+	return err
+}
+
+// DeleteAll 删除用户的全部会话
+func (r *SessionRepository) DeleteAll(ctx context.Context, userID string) error {
+	ids, err := redis.ZRange(ctx, sessionIndexKey(userID), 0, -1)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, sessionKey(userID, id))
+	}
+	keys = append(keys, sessionIndexKey(userID))
+
+	_, err = redis.Del(ctx, keys...)
+	return err
+}
+
+// parseSession 将 Redis Hash 字段转换为会话结构
+func parseSession(fields map[string]string) *model.Session {
+	createdAt, _ := strconv.ParseInt(fields["created_at"], 10, 64)
+	lastSeenAt, _ := strconv.ParseInt(fields["last_seen_at"], 10, 64)
+	return &model.Session{
+		ID:             fields["id"],
+		UserID:         fields["user_id"],
+		Device:         fields["device"],
+		IP:             fields["ip"],
+		UserAgent:      fields["user_agent"],
+		ImpersonatorID: fields["impersonator_id"],
+		AccessTokenID:  fields["access_id"],
+		RefreshTokenID: fields["refresh_id"],
+		CreatedAt:      time.Unix(createdAt, 0),
+		LastSeenAt:     time.Unix(lastSeenAt, 0),
+	}
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hi-go/src/utils/redis"
+	"time"
+)
+
+// 一次性令牌用途
+const (
+	TokenPurposePasswordReset = "password_reset" // 重置密码
+	TokenPurposeEmailVerify   = "email_verify"   // 验证邮箱
+	TokenPurposeOAuthState    = "oauth_state"    // 第三方登录授权请求
+)
+
+// TokenRepository 一次性令牌数据访问层（Redis）
+// Redis 中只保存令牌的 SHA-256 摘要，令牌明文仅通过邮件发送给用户
+type TokenRepository struct{}
+
+// NewTokenRepository 创建一次性令牌仓储实例
+func NewTokenRepository() *TokenRepository {
+	return &TokenRepository{}
+}
+
+// Create 生成一次性令牌，value 为令牌关联的数据，超过 ttl 后自动失效
+func (r *TokenRepository) Create(ctx context.Context, purpose, value string, ttl time.Duration) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := redis.Set(ctx, tokenKey(purpose, token), value, ttl); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Consume 使用令牌并返回关联的数据，令牌随即失效
+// 令牌不存在、已过期或已使用时返回 redis.ErrKeyNotFound
+func (r *TokenRepository) Consume(ctx context.Context, purpose, token string) (string, error) {
+	return redis.GetDel(ctx, tokenKey(purpose, token))
+}
+
+// Peek 读取令牌关联的数据但不使其失效，用于在使用令牌前先校验请求
+// 令牌不存在或已过期时返回 redis.ErrKeyNotFound
+func (r *TokenRepository) Peek(ctx context.Context, purpose, token string) (string, error) {
+	return redis.Get(ctx, tokenKey(purpose, token))
+}
+
+// tokenKey 一次性令牌的 Redis 键
+func tokenKey(purpose, token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("token:%s:%s", purpose, hex.EncodeToString(sum[:]))
+}
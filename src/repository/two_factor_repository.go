@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/utils/mysql"
+)
+
+// TwoFactorRepository 两步验证数据访问层
+type TwoFactorRepository struct{}
+
+// NewTwoFactorRepository 创建两步验证仓储实例
+func NewTwoFactorRepository() *TwoFactorRepository {
+	return &TwoFactorRepository{}
+}
+
+// FindByUserID 根据用户ID查找两步验证配置
+func (r *TwoFactorRepository) FindByUserID(userID int64) (*model.UserTwoFactor, error) {
+	var tf model.UserTwoFactor
+	err := mysql.Database.Where("user_id = ?", userID).First(&tf).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tf, nil
+}
+
+// Save 保存两步验证配置（存在则覆盖）
+func (r *TwoFactorRepository) Save(tf *model.UserTwoFactor) error {
+	return mysql.Database.Save(tf).Error
+}
+
+// UpdateFields 更新指定字段
+func (r *TwoFactorRepository) UpdateFields(userID int64, updates map[string]interface{}) error {
+	return mysql.Database.Model(&model.UserTwoFactor{}).Where("user_id = ?", userID).Updates(updates).Error
+}
+
+// UseStep 记录已使用的时间步，仅当该时间步大于上次记录时成功（防止同一验证码重放）
+func (r *TwoFactorRepository) UseStep(userID, step int64) (bool, error) {
+	result := mysql.Database.Model(&model.UserTwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected > 0, result.Error
+}
+
+// UseRecoveryCodes 用乐观锁更新恢复码，仅当当前值仍为 old 时成功（防止同一恢复码被并发使用）
+func (r *TwoFactorRepository) UseRecoveryCodes(userID int64, old, new string) (bool, error) {
+	result := mysql.Database.Model(&model.UserTwoFactor{}).
+		Where("user_id = ? AND recovery_codes = ?", userID, old).
+		Update("recovery_codes", new)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Delete 删除两步验证配置
+func (r *TwoFactorRepository) Delete(userID int64) error {
+	return mysql.Database.Where("user_id = ?", userID).Delete(&model.UserTwoFactor{}).Error
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/utils/mysql"
+
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository 第三方身份数据访问层
+type UserIdentityRepository struct{}
+
+// NewUserIdentityRepository 创建第三方身份仓储实例
+func NewUserIdentityRepository() *UserIdentityRepository {
+	return &UserIdentityRepository{}
+}
+
+// FindByProviderSubject 根据身份提供方和 subject 查找绑定关系
+func (r *UserIdentityRepository) FindByProviderSubject(provider, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	err := mysql.Database.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// Create 创建绑定关系
+func (r *UserIdentityRepository) Create(identity *model.UserIdentity) error {
+	return mysql.Database.Create(identity).Error
+}
+
+// CreateWithUser 在同一事务中创建用户和绑定关系
+func (r *UserIdentityRepository) CreateWithUser(user *model.User, identity *model.UserIdentity) error {
+	return mysql.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(identity).Error
+	})
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"hi-go/src/model"
 	"hi-go/src/utils/mysql"
+
+	"gorm.io/gorm"
 )
 
 // 用户数据访问层
@@ -33,6 +35,16 @@ func (r *UserRepository) FindByID(id int64) (*model.User, error) {
 	return &user, nil
 }
 
+// FindByEmail 根据邮箱查找用户
+func (r *UserRepository) FindByEmail(email string) (*model.User, error) {
+	var user model.User
+	err := mysql.Database.Where("email = ?", email).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // 创建用户
 func (r *UserRepository) Create(user *model.User) error {
 	return mysql.Database.Create(user).Error
@@ -56,3 +68,91 @@ func (r *UserRepository) ExistsByEmail(email string) (bool, error) {
 	err := mysql.Database.Model(&model.User{}).Where("email = ?", email).Count(&count).Error
 	return count > 0, err
 }
+
+// ExistsByEmailExcept 检查邮箱是否已被其他用户使用
+func (r *UserRepository) ExistsByEmailExcept(email string, excludeID int64) (bool, error) {
+	var count int64
+	err := mysql.Database.Model(&model.User{}).Where("email = ? AND id <> ?", email, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+// List 获取用户列表（分页），deleted 为 true 时只查询已软删除的用户
+func (r *UserRepository) List(keyword string, status *int, deleted bool, page, pageSize int) ([]model.User, int64, error) {
+	var users []model.User
+	var total int64
+
+	// 构建查询
+	query := mysql.Database.Model(&model.User{})
+	if deleted {
+		query = query.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+
+	// 如果有关键词，添加模糊搜索条件
+	if keyword != "" {
+		keyword = "%" + keyword + "%"
+		query = query.Where("username LIKE ? OR nickname LIKE ? OR email LIKE ? OR phone LIKE ?",
+			keyword, keyword, keyword, keyword)
+	}
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	// 查询总数
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	offset := (page - 1) * pageSize
+	if err := query.Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// FindByIDUnscoped 根据ID查找用户（包含已软删除的用户）
+func (r *UserRepository) FindByIDUnscoped(id int64) (*model.User, error) {
+	var user model.User
+	err := mysql.Database.Unscoped().First(&user, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateFields 更新用户指定字段
+func (r *UserRepository) UpdateFields(id int64, updates map[string]interface{}) error {
+	return mysql.Database.Model(&model.User{}).Where("id = ?", id).Updates(updates).Error
+}
+
+// Delete 删除用户（软删除）
+func (r *UserRepository) Delete(id int64) error {
+	return mysql.Database.Delete(&model.User{}, id).Error
+}
+
+// Restore 恢复已软删除的用户
+func (r *UserRepository) Restore(id int64) error {
+	return mysql.Database.Unscoped().Model(&model.User{}).
+		Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+// DeleteAccount 注销账号：在同一事务中匿名化个人信息、软删除用户，
+// 并删除第三方登录绑定、API Key 和两步验证配置
+func (r *UserRepository) DeleteAccount(id int64, anonymized map[string]interface{}) error {
+	return mysql.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.User{}).Where("id = ?", id).Updates(anonymized).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.User{}, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&model.UserIdentity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&model.APIKey{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", id).Delete(&model.UserTwoFactor{}).Error
+	})
+}
@@ -48,6 +48,13 @@ func (r *WebhookRepository) FindBySecret(secret string) (*model.Webhook, error)
 	return &webhook, nil
 }
 
+// FindEnabledByEvent 查找订阅了指定事件的全部已启用 webhook
+func (r *WebhookRepository) FindEnabledByEvent(event string) ([]*model.Webhook, error) {
+	var webhooks []*model.Webhook
+	err := mysql.Database.Where("event = ? AND enabled = ?", event, 1).Find(&webhooks).Error
+	return webhooks, err
+}
+
 // FindByUserID 根据用户 ID 查找 webhook 列表
 func (r *WebhookRepository) FindByUserID(userID int64) ([]*model.Webhook, error) {
 	var webhooks []*model.Webhook
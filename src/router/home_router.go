@@ -3,6 +3,7 @@ package router
 import (
 	"hi-go/src/handler"
 	"hi-go/src/middleware"
+	"hi-go/src/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,22 +12,59 @@ import (
 func SetupHomeRoutes(r *gin.RouterGroup) {
 	// 创建处理器实例
 	homeHandler := handler.NewHomeHandler()
+	slotHandler := handler.NewHomeSlotHandler()
+	statsHandler := handler.NewHomeStatsHandler()
 
 	// 首页模块路由组
 	home := r.Group("/home")
-	home.Use(middleware.JWTAuth())
+	// 需要 JWT 认证（也可使用拥有 home:read 作用域的 API Key，编辑接口还需 home:write 作用域）
+	home.Use(middleware.JWTAuth(model.ScopeHomeRead))
 	{
 		// 获取首页列表
 		home.GET("/list", homeHandler.List)
-		// 创建模拟数据
-		home.POST("/create", homeHandler.Create)
-		// 更新首页内容
-		home.POST("/update", homeHandler.Update)
-		// 删除首页内容
-		home.DELETE("/delete", homeHandler.Delete)
+		// 预览首页列表，包含尚未上线的内容（需要首页编辑权限）
+		home.GET("/preview", middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Preview)
+		// 创建模拟数据（需要首页编辑权限）
+		home.POST("/create", middleware.RequireScope(model.ScopeHomeWrite), middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Create)
+		// 更新首页内容（需要首页编辑权限）
+		home.POST("/update", middleware.RequireScope(model.ScopeHomeWrite), middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Update)
+		// 删除首页内容，移入回收站（需要首页编辑权限）
+		home.DELETE("/delete", middleware.RequireScope(model.ScopeHomeWrite), middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Delete)
+		// 调整排序（需要首页编辑权限）
+		home.POST("/reorder", middleware.RequireScope(model.ScopeHomeWrite), middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Reorder)
+		// 批量导入（需要首页编辑权限）
+		home.POST("/import", middleware.RequireScope(model.ScopeHomeWrite), middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Import)
+		// 导出（需要首页编辑权限）
+		home.GET("/export", middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Export)
+		// 回收站列表（需要首页编辑权限）
+		home.GET("/trash", middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Trash)
+		// 从回收站恢复（需要首页编辑权限）
+		home.POST("/restore", middleware.RequireScope(model.ScopeHomeWrite), middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Restore)
+		// 彻底删除回收站中的内容（仅管理员）
+		home.DELETE("/purge", middleware.RequireScope(model.ScopeHomeWrite), middleware.RoleAuth(model.RoleAdmin), homeHandler.Purge)
 		// 搜索首页内容
 		home.GET("/search", homeHandler.Search)
 		// 根据ID获取首页内容详情
 		home.GET("/detail", homeHandler.GetByID)
+		// 修订记录及比较（需要首页编辑权限）
+		home.GET("/:id/revisions", middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Revisions)
+		home.GET("/:id/revisions/diff", middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.DiffRevisions)
+		// 回滚到历史版本（需要首页编辑权限）
+		home.POST("/:id/rollback", middleware.RequireScope(model.ScopeHomeWrite), middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.Rollback)
+		// 缓存命中统计（需要首页编辑权限）
+		home.GET("/cache/stats", middleware.RequirePermission(model.PermissionHomeWrite), homeHandler.CacheStats)
+
+		// 上报曝光/点击事件
+		home.POST("/track", statsHandler.Track)
+		// 曝光点击报表（需要首页编辑权限）
+		home.GET("/stats/report", middleware.RequirePermission(model.PermissionHomeWrite), statsHandler.Report)
+
+		// 版位列表及详情
+		home.GET("/slot/list", slotHandler.List)
+		home.GET("/slot/detail", slotHandler.GetByID)
+		// 创建、修改、删除版位（需要首页编辑权限）
+		home.POST("/slot/create", middleware.RequireScope(model.ScopeHomeWrite), middleware.RequirePermission(model.PermissionHomeWrite), slotHandler.Create)
+		home.POST("/slot/update", middleware.RequireScope(model.ScopeHomeWrite), middleware.RequirePermission(model.PermissionHomeWrite), slotHandler.Update)
+		home.DELETE("/slot/delete", middleware.RequireScope(model.ScopeHomeWrite), middleware.RequirePermission(model.PermissionHomeWrite), slotHandler.Delete)
 	}
 }
@@ -26,6 +26,9 @@ func Setup() *gin.Engine {
 	// 设置 API 文档路由
 	SetupDocsRoutes(r)
 
+	// 设置 /.well-known 路由（JWKS 公钥）
+	SetupWellKnownRoutes(r)
+
 	// API 路由组
 	api := r.Group("/api")
 	{
@@ -3,6 +3,7 @@ package router
 import (
 	"hi-go/src/handler"
 	"hi-go/src/middleware"
+	"hi-go/src/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -11,6 +12,13 @@ import (
 func SetupUserRoutes(r *gin.RouterGroup) {
 	// 创建处理器实例
 	authHandler := handler.NewAuthHandler()
+	roleHandler := handler.NewRoleHandler()
+	userHandler := handler.NewUserHandler()
+	twoFactorHandler := handler.NewTwoFactorHandler()
+	accountHandler := handler.NewAccountHandler()
+	apiKeyHandler := handler.NewAPIKeyHandler()
+	oauthHandler := handler.NewOAuthHandler()
+	auditHandler := handler.NewAuditHandler()
 
 	// 用户模块路由组
 	user := r.Group("/user")
@@ -18,8 +26,22 @@ func SetupUserRoutes(r *gin.RouterGroup) {
 		// 公开接口（不需要认证）
 		// 登录
 		user.POST("/login", authHandler.Login)
+		// 登录二次验证
+		user.POST("/login/2fa", authHandler.LoginTwoFactor)
 		// 注册
 		user.POST("/register", authHandler.Register)
+		// 刷新令牌
+		user.POST("/refresh", authHandler.Refresh)
+		// 忘记密码
+		user.POST("/password/forgot", accountHandler.ForgotPassword)
+		// 通过邮件链接重置密码
+		user.POST("/password/reset", accountHandler.ResetPassword)
+		// 验证邮箱
+		user.POST("/email/verify", accountHandler.VerifyEmail)
+		// 发起第三方登录
+		user.GET("/oauth/:provider/start", oauthHandler.Start)
+		// 第三方登录回调
+		user.GET("/oauth/:provider/callback", oauthHandler.Callback)
 
 		// 需要认证的接口
 		// JWT 认证中间件
@@ -28,6 +50,40 @@ func SetupUserRoutes(r *gin.RouterGroup) {
 		{
 			// 获取个人信息
 			auth.GET("/profile", authHandler.GetProfile)
+			// 退出登录
+			auth.POST("/logout", authHandler.Logout)
+			// 登录会话列表
+			auth.GET("/sessions", authHandler.ListSessions)
+			// 结束指定会话
+			auth.DELETE("/sessions/:id", authHandler.DeleteSession)
+			// API Key 列表
+			auth.GET("/api-keys", apiKeyHandler.List)
+		}
+
+		// 修改登录凭证的敏感接口，管理员代登录时禁止访问
+		sensitive := user.Group("")
+		sensitive.Use(middleware.JWTAuth(), middleware.DenyImpersonation())
+		{
+			// 修改个人资料（含邮箱）
+			sensitive.PUT("/profile", accountHandler.UpdateProfile)
+			// 修改密码
+			sensitive.POST("/password/change", accountHandler.ChangePassword)
+			// 注销账号
+			sensitive.DELETE("/account", accountHandler.DeleteAccount)
+			// 获取两步验证密钥
+			sensitive.POST("/2fa/enroll", twoFactorHandler.Enroll)
+			// 确认开启两步验证
+			sensitive.POST("/2fa/confirm", twoFactorHandler.Confirm)
+			// 关闭两步验证
+			sensitive.POST("/2fa/disable", twoFactorHandler.Disable)
+			// 重新生成恢复码
+			sensitive.POST("/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+			// 发送邮箱验证邮件
+			sensitive.POST("/email/send-verification", accountHandler.SendVerificationEmail)
+			// 创建 API Key
+			sensitive.POST("/api-keys", apiKeyHandler.Create)
+			// 吊销 API Key
+			sensitive.DELETE("/api-keys/:id", apiKeyHandler.Revoke)
 		}
 
 		// 管理员接口
@@ -35,6 +91,36 @@ func SetupUserRoutes(r *gin.RouterGroup) {
 		// JWT 认证
 		admin.Use(middleware.JWTAuth())
 		// 角色权限认证
-		admin.Use(middleware.RoleAuth("admin"))
+		admin.Use(middleware.RoleAuth(model.RoleAdmin))
+		{
+			// 用户列表
+			admin.GET("/users", userHandler.List)
+			// 用户详情
+			admin.GET("/users/:id", userHandler.GetDetail)
+			// 启用/禁用用户
+			admin.POST("/users/:id/status", userHandler.SetStatus)
+			// 重置密码
+			admin.POST("/users/:id/reset-password", userHandler.ResetPassword)
+			// 删除用户（软删除）
+			admin.DELETE("/users/:id", userHandler.Delete)
+			// 恢复已删除的用户
+			admin.POST("/users/:id/restore", userHandler.Restore)
+			// 设置用户角色
+			admin.PUT("/users/:id/roles", userHandler.SetRoles)
+			// 吊销用户全部会话
+			admin.POST("/users/:id/revoke-sessions", authHandler.RevokeSessions)
+			// 代登录
+			admin.POST("/users/:id/impersonate", authHandler.Impersonate)
+			// 角色列表
+			admin.GET("/roles", roleHandler.ListRoles)
+			// 用户角色
+			admin.GET("/users/:id/roles", roleHandler.GetUserRoles)
+			// 授予角色
+			admin.POST("/users/:id/roles", roleHandler.GrantRole)
+			// 撤销角色
+			admin.DELETE("/users/:id/roles/:role", roleHandler.RevokeRole)
+			// 审计日志
+			admin.GET("/audit-logs", auditHandler.List)
+		}
 	}
 }
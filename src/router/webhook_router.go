@@ -3,6 +3,7 @@ package router
 import (
 	"hi-go/src/handler"
 	"hi-go/src/middleware"
+	"hi-go/src/model"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,8 +15,8 @@ func SetupWebhookRoutes(r *gin.RouterGroup) {
 
 	// Webhook 模块路由组
 	webhook := r.Group("/webhook")
-	// 需要 JWT 认证的路由
-	webhook.Use(middleware.JWTAuth())
+	// 需要 JWT 认证的路由（也可使用拥有 webhook 作用域的 API Key）
+	webhook.Use(middleware.JWTAuth(model.ScopeWebhook))
 	{
 		// 创建 webhook
 		webhook.POST("/create", webhookHandler.Create)
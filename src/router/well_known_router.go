@@ -0,0 +1,18 @@
+package router
+
+import (
+	"hi-go/src/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupWellKnownRoutes 设置 /.well-known 路由（不在 /api 前缀下）
+func SetupWellKnownRoutes(r *gin.Engine) {
+	jwksHandler := handler.NewJWKSHandler()
+
+	wellKnown := r.Group("/.well-known")
+	{
+		// 令牌验证公钥
+		wellKnown.GET("/jwks.json", jwksHandler.GetJWKS)
+	}
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/service/audit"
+	"hi-go/src/utils/logger"
+	"hi-go/src/utils/mailer"
+	"hi-go/src/utils/redis"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidResetToken  = errors.New("重置链接无效或已过期")
+	ErrInvalidVerifyToken = errors.New("验证链接无效或已过期")
+	ErrEmailNotSet        = errors.New("未设置邮箱")
+	ErrEmailVerified      = errors.New("邮箱已验证")
+	ErrMailTooFrequent    = errors.New("邮件发送过于频繁，请稍后再试")
+	ErrInvalidPhone       = errors.New("手机号格式错误")
+	ErrEmailRequired      = errors.New("邮箱不能为空")
+	ErrPasswordIncorrect  = errors.New("密码错误")
+	ErrPasswordUnchanged  = errors.New("新密码不能与原密码相同")
+)
+
+// 同一用户同类邮件的最小发送间隔
+const mailCooldown = time.Minute
+
+// 注销账号后写入的密码，不是有效的 bcrypt 哈希，任何密码都无法通过校验
+const deletedAccountPassword = "!"
+
+// 账号安全业务逻辑层（找回密码、邮箱验证、个人资料、注销账号）
+type AccountService struct {
+	userRepo    *repository.UserRepository
+	tokenRepo   *repository.TokenRepository
+	sessionRepo *repository.SessionRepository
+	twoFactor   *TwoFactorService
+}
+
+// 创建账号安全服务实例
+func NewAccountService() *AccountService {
+	return &AccountService{
+		userRepo:    repository.NewUserRepository(),
+		tokenRepo:   repository.NewTokenRepository(),
+		sessionRepo: repository.NewSessionRepository(),
+		twoFactor:   NewTwoFactorService(),
+	}
+}
+
+// ForgotPassword 发送重置密码邮件
+// 邮箱不存在或用户被禁用时同样返回成功，避免泄露邮箱是否已注册
+func (s *AccountService) ForgotPassword(email string) error {
+	user, err := s.userRepo.FindByEmail(email)
+	if err != nil || user.Status != 1 {
+		return nil
+	}
+
+	ctx := context.Background()
+	if !s.acquireMailCooldown(ctx, repository.TokenPurposePasswordReset, user.ID) {
+		return nil
+	}
+
+	token, err := s.tokenRepo.Create(ctx, repository.TokenPurposePasswordReset,
+		fmt.Sprintf("%d", user.ID), config.GetMailResetTokenTTL())
+	if err != nil {
+		return err
+	}
+
+	return mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "重置密码",
+		Body: fmt.Sprintf("%s，您好：\n\n请点击以下链接重置密码，链接 %d 分钟内有效：\n%s\n\n如果这不是您本人的操作，请忽略本邮件。",
+			user.Username, int(config.GetMailResetTokenTTL().Minutes()), buildMailLink("/reset-password", token)),
+	})
+}
+
+// ResetPassword 使用邮件中的令牌重置密码，并吊销该用户的全部会话
+// 新密码不符合密码策略时令牌不会失效，用户可以换一个密码重试
+func (s *AccountService) ResetPassword(ctx context.Context, token, password string) error {
+	// 1. 读取令牌关联的用户
+	value, err := s.tokenRepo.Peek(ctx, repository.TokenPurposePasswordReset, token)
+	if errors.Is(err, redis.ErrKeyNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	var userID int64
+	if _, err := fmt.Sscanf(value, "%d", &userID); err != nil {
+		return ErrInvalidResetToken
+	}
+
+	// 2. 检查用户状态和新密码
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	if user.Status != 1 {
+		return ErrUserDisabled
+	}
+	if err := checkPassword(password, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	// 3. 使用令牌（一次性），并发请求中只有一个能成功
+	consumed, err := s.tokenRepo.Consume(ctx, repository.TokenPurposePasswordReset, token)
+	if errors.Is(err, redis.ErrKeyNotFound) || (err == nil && consumed != value) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	// 4. 更新密码
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{"password": string(hashedPassword)}); err != nil {
+		return err
+	}
+
+	// 5. 吊销全部会话，已登录的设备需要使用新密码重新登录
+	if err := s.sessionRepo.DeleteAll(ctx, fmt.Sprintf("%d", userID)); err != nil {
+		return err
+	}
+	audit.Record(ctx, userID, audit.ActionPasswordReset, audit.Target("user", userID), nil)
+	return nil
+}
+
+// SendVerificationEmail 向用户当前邮箱发送验证邮件
+func (s *AccountService) SendVerificationEmail(userID int64) error {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if user.Email == "" {
+		return ErrEmailNotSet
+	}
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailVerified
+	}
+
+	ctx := context.Background()
+	if !s.acquireMailCooldown(ctx, repository.TokenPurposeEmailVerify, user.ID) {
+		return ErrMailTooFrequent
+	}
+
+	// 令牌同时绑定邮箱，修改邮箱后旧的验证链接自动失效
+	token, err := s.tokenRepo.Create(ctx, repository.TokenPurposeEmailVerify,
+		fmt.Sprintf("%d:%s", user.ID, user.Email), config.GetMailVerifyTokenTTL())
+	if err != nil {
+		return err
+	}
+
+	return mailer.Send(ctx, &mailer.Message{
+		To:      user.Email,
+		Subject: "验证邮箱",
+		Body: fmt.Sprintf("%s，您好：\n\n请点击以下链接验证您的邮箱，链接 %d 小时内有效：\n%s\n\n如果这不是您本人的操作，请忽略本邮件。",
+			user.Username, int(config.GetMailVerifyTokenTTL().Hours()), buildMailLink("/verify-email", token)),
+	})
+}
+
+// VerifyEmail 使用邮件中的令牌验证邮箱
+func (s *AccountService) VerifyEmail(token string) error {
+	value, err := s.tokenRepo.Consume(context.Background(), repository.TokenPurposeEmailVerify, token)
+	if errors.Is(err, redis.ErrKeyNotFound) {
+		return ErrInvalidVerifyToken
+	}
+	if err != nil {
+		return err
+	}
+
+	idStr, email, ok := strings.Cut(value, ":")
+	if !ok {
+		return ErrInvalidVerifyToken
+	}
+	var userID int64
+	if _, err := fmt.Sscanf(idStr, "%d", &userID); err != nil {
+		return ErrInvalidVerifyToken
+	}
+
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil || user.Email != email {
+		return ErrInvalidVerifyToken
+	}
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	return s.userRepo.UpdateFields(userID, map[string]interface{}{"email_verified_at": &now})
+}
+
+// UpdateProfile 修改个人资料，邮箱变更后需要重新验证
+func (s *AccountService) UpdateProfile(userID int64, req *model.UpdateProfileRequest) (*model.User, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	// 1. 收集需要修改的字段
+	updates := make(map[string]interface{})
+	if req.Nickname != nil {
+		updates["nickname"] = strings.TrimSpace(*req.Nickname)
+	}
+	if req.Avatar != nil {
+		updates["avatar"] = strings.TrimSpace(*req.Avatar)
+	}
+	if req.Phone != nil {
+		phone := strings.TrimSpace(*req.Phone)
+		if phone != "" && !isValidPhone(phone) {
+			return nil, ErrInvalidPhone
+		}
+		updates["phone"] = phone
+	}
+	emailChanged := false
+	// 邮箱有唯一索引且用于找回密码，不允许清空
+	if req.Email != nil && strings.TrimSpace(*req.Email) == "" {
+		return nil, ErrEmailRequired
+	}
+	if req.Email != nil && !strings.EqualFold(*req.Email, user.Email) {
+		exists, err := s.userRepo.ExistsByEmailExcept(*req.Email, userID)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrEmailExists
+		}
+		updates["email"] = *req.Email
+		updates["email_verified_at"] = nil
+		emailChanged = true
+	}
+	if len(updates) == 0 {
+		return user, nil
+	}
+
+	// 2. 更新资料
+	if err := s.userRepo.UpdateFields(userID, updates); err != nil {
+		return nil, err
+	}
+
+	// 3. 向新邮箱发送验证邮件，发送失败不影响修改结果，用户可稍后重新发送
+	if emailChanged {
+		go func() {
+			if err := s.SendVerificationEmail(userID); err != nil {
+				logger.Warn("发送邮箱验证邮件失败", zap.Int64("userID", userID), zap.Error(err))
+			}
+		}()
+	}
+
+	return s.userRepo.FindByID(userID)
+}
+
+// ChangePassword 校验原密码后修改密码，并吊销当前会话以外的全部会话
+func (s *AccountService) ChangePassword(ctx context.Context, userID int64, sessionID string, req *model.ChangePasswordRequest) error {
+	// 1. 校验原密码和新密码
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); err != nil {
+		return ErrPasswordIncorrect
+	}
+	if req.NewPassword == req.OldPassword {
+		return ErrPasswordUnchanged
+	}
+	if err := checkPassword(req.NewPassword, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	// 2. 更新密码
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdateFields(userID, map[string]interface{}{"password": string(hashedPassword)}); err != nil {
+		return err
+	}
+
+	// 3. 吊销其他设备上的会话，当前会话保持登录
+	uid := fmt.Sprintf("%d", userID)
+	sessions, err := s.sessionRepo.List(ctx, uid)
+	if err != nil {
+		return err
+	}
+	revoked := 0
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			continue
+		}
+		if err := s.sessionRepo.Delete(ctx, uid, session.ID); err != nil {
+			return err
+		}
+		revoked++
+	}
+	audit.Record(ctx, userID, audit.ActionPasswordChange, audit.Target("user", userID), map[string]interface{}{
+		"sessions_revoked": revoked,
+	})
+	return nil
+}
+
+// DeleteAccount 注销账号：校验密码（及两步验证码）后匿名化个人信息并软删除用户，
+// 同时解除第三方登录绑定、删除 API Key 和两步验证配置，吊销全部会话
+func (s *AccountService) DeleteAccount(ctx context.Context, userID int64, req *model.DeleteAccountRequest) error {
+	// 1. 校验密码
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return ErrPasswordIncorrect
+	}
+
+	// 2. 已开启两步验证时校验验证码
+	enabled, err := s.twoFactor.IsEnabled(userID)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		if err := s.twoFactor.Verify(userID, req.Code); err != nil {
+			return err
+		}
+	}
+
+	// 3. 匿名化并删除，用户名和邮箱随即释放，可被重新注册
+	anonymized := map[string]interface{}{
+		"username":          fmt.Sprintf("deleted_%d", userID),
+		"password":          deletedAccountPassword,
+		"email":             nil,
+		"email_verified_at": nil,
+		"phone":             "",
+		"nickname":          "",
+		"avatar":            "",
+		"status":            0,
+	}
+	if err := s.userRepo.DeleteAccount(userID, anonymized); err != nil {
+		return err
+	}
+
+	// 4. 吊销全部会话
+	if err := s.sessionRepo.DeleteAll(ctx, fmt.Sprintf("%d", userID)); err != nil {
+		return err
+	}
+	audit.Record(ctx, userID, audit.ActionAccountDelete, audit.Target("user", userID), nil)
+	return nil
+}
+
+// acquireMailCooldown 限制同一用户同类邮件的发送频率，返回 false 表示仍在冷却中
+func (s *AccountService) acquireMailCooldown(ctx context.Context, purpose string, userID int64) bool {
+	ok, err := redis.SetNX(ctx, fmt.Sprintf("mail:cooldown:%s:%d", purpose, userID), 1, mailCooldown)
+	if err != nil {
+		// Redis 异常时不阻断发送
+		logger.Warn("检查邮件发送频率失败", zap.Int64("userID", userID), zap.Error(err))
+		return true
+	}
+	return ok
+}
+
+// buildMailLink 生成邮件中带令牌的前端链接
+func buildMailLink(path, token string) string {
+	return strings.TrimRight(config.Config.Mail.LinkBaseURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+// isValidPhone 检查手机号是否为 11 位数字
+func isValidPhone(phone string) bool {
+	if len(phone) != 11 {
+		return false
+	}
+	for _, ch := range phone {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"errors"
+	"hi-go/src/model"
+	"hi-go/src/utils/testutil"
+	"testing"
+)
+
+func TestUpdateProfileRejectsEmptyEmail(t *testing.T) {
+	testutil.SetupConfig(t, nil)
+	db := testutil.SetupDB(t, &model.User{})
+
+	users := []model.User{
+		{ID: 1, Username: "alice", Email: "alice@example.com", Status: 1},
+		{ID: 2, Username: "bob", Email: "bob@example.com", Status: 1},
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// 两个用户都尝试清空邮箱，都应返回参数错误而不是撞上唯一索引
+	s := NewAccountService()
+	for _, user := range users {
+		for _, email := range []string{"", "  "} {
+			if _, err := s.UpdateProfile(user.ID, &model.UpdateProfileRequest{Email: &email}); !errors.Is(err, ErrEmailRequired) {
+				t.Fatalf("%s 清空邮箱 %q err = %v，期望 ErrEmailRequired", user.Username, email, err)
+			}
+		}
+	}
+
+	var got model.User
+	if err := db.First(&got, 1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.Email != "alice@example.com" {
+		t.Fatalf("邮箱被修改为 %q", got.Email)
+	}
+}
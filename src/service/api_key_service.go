@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/service/audit"
+	"hi-go/src/utils/logger"
+	"hi-go/src/utils/snowflake"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAPIKeyInvalid      = errors.New("API Key 无效")
+	ErrAPIKeyExpired      = errors.New("API Key 已过期")
+	ErrAPIKeyNotFound     = errors.New("API Key 不存在")
+	ErrAPIKeyLimit        = errors.New("API Key 数量已达上限")
+	ErrAPIKeyExpiresAtOld = errors.New("过期时间必须晚于当前时间")
+)
+
+const (
+	// API Key 明文前缀，便于识别和密钥扫描
+	apiKeyPrefix = "hgk_"
+	// 每个用户最多可创建的 API Key 数量
+	maxAPIKeysPerUser = 20
+	// 最近使用时间的更新间隔
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyPrincipal API Key 认证通过后的调用方信息
+type APIKeyPrincipal struct {
+	Key   *model.APIKey
+	User  *model.User
+	Roles []string
+}
+
+// API Key 业务逻辑层
+type APIKeyService struct {
+	apiKeyRepo  *repository.APIKeyRepository
+	userRepo    *repository.UserRepository
+	roleService *RoleService
+}
+
+// 创建 API Key 服务实例
+func NewAPIKeyService() *APIKeyService {
+	return &APIKeyService{
+		apiKeyRepo:  repository.NewAPIKeyRepository(),
+		userRepo:    repository.NewUserRepository(),
+		roleService: NewRoleService(),
+	}
+}
+
+// Create 创建 API Key，明文只在返回值中出现一次
+func (s *APIKeyService) Create(ctx context.Context, userID int64, req *model.APIKeyCreateRequest) (*model.APIKeyCreateResponse, error) {
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrAPIKeyExpiresAtOld
+	}
+
+	count, err := s.apiKeyRepo.CountByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAPIKeysPerUser {
+		return nil, fmt.Errorf("%w（%d 个）", ErrAPIKeyLimit, maxAPIKeysPerUser)
+	}
+
+	// 生成随机 Key
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	raw := apiKeyPrefix + hex.EncodeToString(buf)
+
+	key := &model.APIKey{
+		ID:        snowflake.MustGenerate(),
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    raw[:len(apiKeyPrefix)+8],
+		KeyHash:   hashAPIKey(raw),
+		Scopes:    uniqueStrings(req.Scopes),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, err
+	}
+	audit.Record(ctx, userID, audit.ActionAPIKeyCreate, audit.Target("api_key", key.ID), map[string]interface{}{
+		"name":       key.Name,
+		"prefix":     key.Prefix,
+		"scopes":     key.Scopes,
+		"expires_at": key.ExpiresAt,
+	})
+
+	return &model.APIKeyCreateResponse{APIKey: key, Key: raw}, nil
+}
+
+// List 获取用户的 API Key 列表
+func (s *APIKeyService) List(userID int64) ([]model.APIKey, error) {
+	return s.apiKeyRepo.ListByUserID(userID)
+}
+
+// Revoke 吊销（删除）用户的 API Key
+func (s *APIKeyService) Revoke(ctx context.Context, userID, id int64) error {
+	ok, err := s.apiKeyRepo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrAPIKeyNotFound
+	}
+	audit.Record(ctx, userID, audit.ActionAPIKeyRevoke, audit.Target("api_key", id), nil)
+	return nil
+}
+
+// Authenticate 校验 API Key，返回所属用户及其角色
+func (s *APIKeyService) Authenticate(raw, ip string) (*APIKeyPrincipal, error) {
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, ErrAPIKeyInvalid
+	}
+
+	// 1. 根据摘要查找 Key
+	key, err := s.apiKeyRepo.FindByHash(hashAPIKey(raw))
+	if err != nil {
+		return nil, ErrAPIKeyInvalid
+	}
+	now := time.Now()
+	if key.ExpiresAt != nil && now.After(*key.ExpiresAt) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	// 2. 检查所属用户状态
+	user, err := s.userRepo.FindByID(key.UserID)
+	if err != nil {
+		return nil, ErrAPIKeyInvalid
+	}
+	if user.Status != 1 {
+		return nil, ErrUserDisabled
+	}
+
+	// 3. 加载用户当前角色，API Key 的权限不超过用户本身
+	roles, err := s.roleService.GetUserRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 记录最近使用时间（限频）
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		if err := s.apiKeyRepo.UpdateLastUsed(key.ID, now, ip); err != nil {
+			logger.Warn("更新 API Key 使用时间失败", zap.Int64("apiKeyID", key.ID), zap.Error(err))
+		}
+	}
+
+	return &APIKeyPrincipal{Key: key, User: user, Roles: roles}, nil
+}
+
+// hashAPIKey 计算 API Key 摘要
+// Key 本身为高熵随机串，使用 SHA-256 即可，无需 bcrypt 这类慢哈希
+func hashAPIKey(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// uniqueStrings 去除重复元素并保持原有顺序
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,134 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/utils/logger"
+	"hi-go/src/utils/snowflake"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// 操作类型
+const (
+	ActionLogin               = "auth.login"                // 登录成功
+	ActionLoginFailed         = "auth.login_failed"         // 登录失败（密码错误、用户被禁用、被锁定、两步验证失败）
+	ActionLogout              = "auth.logout"               // 退出登录
+	ActionRegister            = "auth.register"             // 注册
+	ActionSessionsRevoked     = "auth.sessions_revoked"     // 管理员吊销用户全部会话
+	ActionImpersonate         = "auth.impersonate"          // 管理员代登录
+	ActionImpersonatedRequest = "auth.impersonated_request" // 代登录期间的请求
+	ActionTokenRefresh        = "auth.token_refresh"        // 使用刷新令牌轮换令牌
+
+	ActionPasswordReset  = "account.password_reset"  // 通过邮件令牌重置密码
+	ActionPasswordChange = "account.password_change" // 修改密码
+	ActionAccountDelete  = "account.delete"          // 注销账号
+
+	ActionTwoFactorEnable        = "two_factor.enable"         // 开启两步验证
+	ActionTwoFactorDisable       = "two_factor.disable"        // 关闭两步验证
+	ActionTwoFactorRecoveryCodes = "two_factor.recovery_codes" // 重新生成恢复码
+
+	ActionAPIKeyCreate = "api_key.create" // 创建 API Key
+	ActionAPIKeyRevoke = "api_key.revoke" // 吊销 API Key
+
+	ActionUserStatus        = "user.status"         // 管理员启用/禁用用户
+	ActionUserResetPassword = "user.reset_password" // 管理员重置密码
+	ActionUserDelete        = "user.delete"         // 管理员删除用户
+	ActionUserRestore       = "user.restore"        // 管理员恢复用户
+	ActionUserRoles         = "user.roles"          // 管理员设置用户角色
+	ActionUserRoleGrant     = "user.role_grant"     // 管理员授予用户角色
+	ActionUserRoleRevoke    = "user.role_revoke"    // 管理员撤销用户角色
+
+	ActionWebhookCreate = "webhook.create" // 创建 webhook
+	ActionWebhookUpdate = "webhook.update" // 修改 webhook
+	ActionWebhookDelete = "webhook.delete" // 删除 webhook
+	ActionWebhookSign   = "webhook.sign"   // 获取 webhook 签名（会返回密钥）
+
+	ActionHomeMock     = "home.mock"     // 生成首页模拟数据
+	ActionHomeUpdate   = "home.update"   // 修改首页内容
+	ActionHomeDelete   = "home.delete"   // 删除首页内容（移入回收站）
+	ActionHomeRestore  = "home.restore"  // 从回收站恢复首页内容
+	ActionHomePurge    = "home.purge"    // 彻底删除首页内容（管理员操作或回收站自动清理）
+	ActionHomeImport   = "home.import"   // 批量导入首页内容
+	ActionHomeExport   = "home.export"   // 导出首页内容
+	ActionHomeReorder  = "home.reorder"  // 调整首页内容排序
+	ActionHomeRollback = "home.rollback" // 回滚首页内容到历史版本
+
+	ActionHomeSlotCreate = "home_slot.create" // 创建首页版位
+	ActionHomeSlotUpdate = "home_slot.update" // 修改首页版位
+	ActionHomeSlotDelete = "home_slot.delete" // 删除首页版位
+)
+
+var repo = repository.NewAuditLogRepository()
+
+// Target 生成操作对象标识，如 user:123
+func Target(kind string, id int64) string {
+	return fmt.Sprintf("%s:%d", kind, id)
+}
+
+// Record 记录审计日志
+// ctx 为 gin.Context 时自动记录追踪ID、客户端IP、User-Agent、API Key ID 和代登录的管理员ID。
+// 写入失败只记录错误日志，不影响业务流程
+func Record(ctx context.Context, actorID int64, action, target string, metadata map[string]interface{}) {
+	entry := &model.AuditLog{
+		ID:       snowflake.MustGenerate(),
+		ActorID:  actorID,
+		Action:   action,
+		Target:   target,
+		Metadata: metadata,
+	}
+	if c, ok := ctx.(*gin.Context); ok {
+		entry.TraceID = model.GetTraceID(c)
+		entry.IP = c.ClientIP()
+		entry.UserAgent = truncate(c.Request.UserAgent(), 255)
+		entry.APIKeyID = c.GetInt64("apiKeyID")
+		entry.ImpersonatorID, _ = strconv.ParseInt(c.GetString("impersonatorID"), 10, 64)
+	}
+
+	if err := repo.Create(entry); err != nil {
+		logger.Error("写入审计日志失败",
+			zap.String("action", action),
+			zap.Int64("actorID", actorID),
+			zap.String("target", target),
+			zap.String("traceID", entry.TraceID),
+			zap.Error(err))
+	}
+}
+
+// List 分页查询审计日志
+func List(req *model.AuditLogListRequest) (*model.AuditLogListDataResponse, error) {
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = config.Config.Business.DefaultPageSize
+	}
+	if req.PageSize > config.Config.Business.MaxPageSize {
+		req.PageSize = config.Config.Business.MaxPageSize
+	}
+
+	list, total, err := repo.List(req.ActorID, req.Action, req.StartTime, req.EndTime, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuditLogListDataResponse{
+		List:  list,
+		Total: total,
+	}, nil
+}
+
+// truncate 按字符数截断字符串
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -2,86 +2,535 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"hi-go/src/config"
 	"hi-go/src/model"
 	"hi-go/src/repository"
+	"hi-go/src/service/audit"
 	"hi-go/src/utils/jwt"
+	"hi-go/src/utils/logger"
 	"hi-go/src/utils/redis"
 	"hi-go/src/utils/snowflake"
+	"time"
 
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var (
-	ErrInvalidCredentials = errors.New("用户名或密码错误")
-	ErrUserNotFound       = errors.New("用户不存在")
-	ErrUserDisabled       = errors.New("用户已被禁用")
-	ErrUserExists         = errors.New("用户名已存在")
-	ErrEmailExists        = errors.New("邮箱已存在")
+	ErrInvalidCredentials  = errors.New("用户名或密码错误")
+	ErrUserNotFound        = errors.New("用户不存在")
+	ErrUserDisabled        = errors.New("用户已被禁用")
+	ErrUserExists          = errors.New("用户名已存在")
+	ErrEmailExists         = errors.New("邮箱已存在")
+	ErrInvalidRefreshToken = errors.New("刷新令牌无效")
+	ErrRefreshTokenRevoked = errors.New("刷新令牌已失效，请重新登录")
+	ErrSessionNotFound     = errors.New("会话不存在或已失效")
+	ErrChallengeInvalid    = errors.New("登录验证已失效，请重新登录")
+	ErrImpersonateAdmin    = errors.New("不能代登录管理员账号")
+)
+
+// 两步验证登录挑战允许的最大尝试次数
+const maxChallengeAttempts = 5
+
+// 登录方式，写入审计日志
+const (
+	loginMethodPassword  = "password"
+	loginMethodTwoFactor = "+2fa" // 追加在原登录方式之后，如 password+2fa
 )
 
 // 认证服务
 type AuthService struct {
-	userRepo *repository.UserRepository
+	userRepo    *repository.UserRepository
+	sessionRepo *repository.SessionRepository
+	roleService *RoleService
+	twoFactor   *TwoFactorService
+	limiter     *loginLimiter
 }
 
 // 创建认证服务实例
 func NewAuthService() *AuthService {
 	return &AuthService{
-		userRepo: repository.NewUserRepository(),
+		userRepo:    repository.NewUserRepository(),
+		sessionRepo: repository.NewSessionRepository(),
+		roleService: NewRoleService(),
+		twoFactor:   NewTwoFactorService(),
+		limiter:     &loginLimiter{},
 	}
 }
 
 // 登录用户
-func (s *AuthService) Login(req *model.LoginRequest) (*model.LoginDataResponse, error) {
-	// 1. 查找用户
+func (s *AuthService) Login(ctx context.Context, req *model.LoginRequest, client *model.ClientInfo) (*model.LoginDataResponse, error) {
+	if client == nil {
+		client = &model.ClientInfo{}
+	}
+
+	// 1. 检查用户名或IP是否因失败次数过多被锁定
+	if err := s.limiter.check(req.Username, client.IP); err != nil {
+		auditLoginFailed(ctx, 0, req.Username, "locked")
+		return nil, err
+	}
+
+	// 2. 查找用户
 	user, err := s.userRepo.FindByUsername(req.Username)
 	if err != nil {
-		return nil, ErrInvalidCredentials
+		auditLoginFailed(ctx, 0, req.Username, "user_not_found")
+		return nil, s.loginFailed(req.Username, client.IP)
 	}
 
-	// 2. 检查用户状态
+	// 3. 检查用户状态
 	if user.Status != 1 {
+		auditLoginFailed(ctx, user.ID, req.Username, "disabled")
 		return nil, ErrUserDisabled
 	}
 
-	// 3. 验证密码
+	// 4. 验证密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, ErrInvalidCredentials
+		auditLoginFailed(ctx, user.ID, req.Username, "invalid_password")
+		return nil, s.loginFailed(req.Username, client.IP)
 	}
 
-	// 4. 生成 JWT Token
-	userID := fmt.Sprintf("%d", user.ID)
+	// 5. 完成登录（两步验证或签发令牌）
+	// 需要两步验证时不清除失败计数，验证码错误继续累计，会话创建后才清除
+	resp, err := s.completeLogin(ctx, user, req.Device, client, loginMethodPassword)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.TwoFactorRequired {
+		s.limiter.reset(req.Username)
+	}
+	return resp, nil
+}
+
+// completeLogin 用户身份校验通过后完成登录
+// 已开启两步验证时先返回挑战令牌，验证码通过后再签发令牌；否则直接创建会话
+// method 为登录方式（password、oauth:{provider}），记录在审计日志中
+func (s *AuthService) completeLogin(ctx context.Context, user *model.User, device string, client *model.ClientInfo, method string) (*model.LoginDataResponse, error) {
+	enabled, err := s.twoFactor.IsEnabled(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		challengeToken, err := s.createChallenge(ctx, user.ID, device, method)
+		if err != nil {
+			return nil, err
+		}
+		return &model.LoginDataResponse{
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
+		}, nil
+	}
+
+	return s.createSession(ctx, user, device, client, method)
+}
 
-	// 可以从数据库获取角色列表，这里简化为固定角色
-	roles := []string{"user"}
+// LoginTwoFactor 登录二次验证，校验挑战令牌和验证码后签发令牌
+func (s *AuthService) LoginTwoFactor(ctx context.Context, req *model.LoginTwoFactorRequest, client *model.ClientInfo) (*model.LoginDataResponse, error) {
+	if client == nil {
+		client = &model.ClientInfo{}
+	}
+	key := challengeKey(req.ChallengeToken)
 
-	// 使用 GenerateTokenPair 生成 access token 和 refresh token
-	accessToken, refreshToken, err := jwt.GenerateTokenPair(userID, user.Username, roles, nil)
+	// 1. 读取挑战信息
+	data, err := redis.HGetAll(ctx, key)
 	if err != nil {
 		return nil, err
 	}
+	if len(data) == 0 {
+		return nil, ErrChallengeInvalid
+	}
+	var userID int64
+	if _, err := fmt.Sscanf(data["user_id"], "%d", &userID); err != nil {
+		return nil, ErrChallengeInvalid
+	}
 
-	// 5. 要将accessToken信息存储到redis中，方便后续验证和刷新token时使用
-	redisKey := fmt.Sprintf("jwt:access_token:%s", userID)
-	err = redis.Set(context.Background(), redisKey, accessToken, config.GetRedisTokenTTL())
+	// 2. 累计尝试次数，超过上限后挑战作废
+	attempts, err := redis.HIncrBy(ctx, key, "attempts", 1)
 	if err != nil {
 		return nil, err
 	}
+	if attempts > maxChallengeAttempts {
+		redis.Del(ctx, key)
+		return nil, ErrChallengeInvalid
+	}
+
+	// 3. 检查用户状态和锁定状态
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Status != 1 {
+		auditLoginFailed(ctx, user.ID, user.Username, "disabled")
+		return nil, ErrUserDisabled
+	}
+	if err := s.limiter.check(user.Username, client.IP); err != nil {
+		auditLoginFailed(ctx, user.ID, user.Username, "locked")
+		return nil, err
+	}
+
+	// 4. 校验验证码或恢复码，失败次数与密码错误一起计入登录失败，防止反复登录后继续尝试验证码
+	if err := s.twoFactor.Verify(userID, req.Code); err != nil {
+		auditLoginFailed(ctx, user.ID, user.Username, "invalid_2fa_code")
+		if lockErr := s.limiter.recordFailure(user.Username, client.IP); lockErr != nil {
+			return nil, lockErr
+		}
+		return nil, err
+	}
+
+	// 5. 挑战只能使用一次，删除成功者才能创建会话
+	deleted, err := redis.Del(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, ErrChallengeInvalid
+	}
+
+	resp, err := s.createSession(ctx, user, data["device"], client, data["method"]+loginMethodTwoFactor)
+	if err != nil {
+		return nil, err
+	}
+	s.limiter.reset(user.Username)
+	return resp, nil
+}
+
+// createChallenge 为通过密码校验的用户创建两步验证登录挑战
+func (s *AuthService) createChallenge(ctx context.Context, userID int64, device, method string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+
+	key := challengeKey(token)
+	_, err := redis.TxPipeline(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, key, "user_id", userID, "device", device, "method", method, "attempts", 0)
+		pipe.Expire(ctx, key, config.GetTwoFactorChallengeTTL())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// challengeKey 两步验证登录挑战的 Redis 键
+func challengeKey(token string) string {
+	return fmt.Sprintf("login:2fa_challenge:%s", token)
+}
+
+// loginFailed 记录登录失败，达到阈值时返回锁定错误，否则返回用户名或密码错误
+func (s *AuthService) loginFailed(username, ip string) error {
+	if err := s.limiter.recordFailure(username, ip); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
+
+// auditLoginFailed 记录登录失败的审计日志，actorID 为 0 表示用户不存在或尚未识别
+func auditLoginFailed(ctx context.Context, actorID int64, username, reason string) {
+	target := ""
+	if actorID != 0 {
+		target = audit.Target("user", actorID)
+	}
+	audit.Record(ctx, actorID, audit.ActionLoginFailed, target, map[string]interface{}{
+		"username": username,
+		"reason":   reason,
+	})
+}
+
+// createSession 为用户创建新的登录会话并签发令牌对
+// 每次登录对应一个会话，不同设备的登录互不影响
+func (s *AuthService) createSession(ctx context.Context, user *model.User, device string, client *model.ClientInfo, method string) (*model.LoginDataResponse, error) {
+	userID := fmt.Sprintf("%d", user.ID)
+	sessionID := uuid.New().String()
+
+	// 从数据库加载用户角色
+	roles, err := s.roleService.GetUserRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 使用 GenerateSessionTokenPair 生成绑定会话的 access token 和 refresh token
+	accessToken, refreshToken, err := jwt.GenerateSessionTokenPair(sessionID, userID, user.Username, roles, nil)
+	if err != nil {
+		return nil, err
+	}
+	accessClaims, err := jwt.ParseToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	refreshClaims, err := jwt.ParseToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// 将会话信息存储到redis中，JWTAuth 和刷新令牌时据此校验
+	if client == nil {
+		client = &model.ClientInfo{}
+	}
+	now := time.Now()
+	session := &model.Session{
+		ID:             sessionID,
+		UserID:         userID,
+		Device:         device,
+		IP:             client.IP,
+		UserAgent:      client.UserAgent,
+		AccessTokenID:  accessClaims.ID,
+		RefreshTokenID: refreshClaims.ID,
+		CreatedAt:      now,
+		LastSeenAt:     now,
+	}
+	if err := s.sessionRepo.Create(ctx, session, config.GetJWTRefreshTokenDuration()); err != nil {
+		return nil, err
+	}
+
+	// 超出会话数上限时，踢掉最早登录的会话
+	if err := s.evictSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, user.ID, audit.ActionLogin, audit.Target("user", user.ID), map[string]interface{}{
+		"method":     method,
+		"session_id": sessionID,
+		"device":     device,
+	})
 
-	// 6. 返回登录响应
 	return &model.LoginDataResponse{
 		User:         user,
+		SessionID:    sessionID,
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		ExpiresIn:    int64(config.JWTAccessTokenDuration), // 使用配置常量
 	}, nil
 }
 
+// evictSessions 按配置的会话数上限删除最早创建的会话
+// 管理员代登录的会话不计入上限，也不会被踢掉，到期后自动失效
+func (s *AuthService) evictSessions(ctx context.Context, userID string) error {
+	maxSessions := config.Config.JWT.MaxSessionsPerUser
+	if maxSessions <= 0 {
+		return nil
+	}
+
+	all, err := s.sessionRepo.List(ctx, userID)
+	if err != nil {
+		return err
+	}
+	sessions := make([]*model.Session, 0, len(all))
+	for _, session := range all {
+		if session.ImpersonatorID == "" {
+			sessions = append(sessions, session)
+		}
+	}
+	for i := 0; i < len(sessions)-maxSessions; i++ {
+		if err := s.sessionRepo.Delete(ctx, userID, sessions[i].ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshToken 使用刷新令牌换取新的令牌对
+// 刷新令牌只能使用一次：成功后旧的刷新令牌立即失效，并签发新的刷新令牌
+func (s *AuthService) RefreshToken(ctx context.Context, req *model.RefreshTokenRequest) (*model.LoginDataResponse, error) {
+	// 1. 解析刷新令牌，并确认类型为 refresh
+	claims, err := jwt.ParseToken(req.RefreshToken)
+	if err != nil || claims.TokenType != jwt.TokenTypeRefresh {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// 2. 检查用户状态
+	var id int64
+	if _, err := fmt.Sscanf(claims.UserID, "%d", &id); err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Status != 1 {
+		return nil, ErrUserDisabled
+	}
+
+	// 3. 重新加载角色，生成同一会话下新的访问令牌和刷新令牌
+	roles, err := s.roleService.GetUserRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := jwt.RefreshAccessToken(req.RefreshToken, user.Username, roles, nil)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	refreshToken, err := jwt.GenerateSessionRefreshToken(claims.SessionID, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	accessClaims, err := jwt.ParseToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	refreshClaims, err := jwt.ParseToken(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 在 Redis 中轮换会话令牌，旧的刷新令牌随即失效
+	err = s.sessionRepo.Rotate(ctx, claims.UserID, claims.SessionID,
+		claims.ID, accessClaims.ID, refreshClaims.ID, config.GetJWTRefreshTokenDuration())
+	if errors.Is(err, redis.ErrKeyNotFound) || errors.Is(err, repository.ErrSessionTokenMismatch) {
+		return nil, ErrRefreshTokenRevoked
+	}
+	if err != nil {
+		return nil, err
+	}
+	audit.Record(ctx, user.ID, audit.ActionTokenRefresh, audit.Target("user", user.ID), map[string]interface{}{
+		"session_id": claims.SessionID,
+	})
+
+	return &model.LoginDataResponse{
+		User:         user,
+		SessionID:    claims.SessionID,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(config.JWTAccessTokenDuration),
+	}, nil
+}
+
+// Impersonate 管理员代登录：为目标用户签发短期访问令牌（不签发刷新令牌）
+// 令牌的 Extra 中携带实际操作人，会话记录管理员ID，用户可在会话列表中看到并结束该会话
+func (s *AuthService) Impersonate(ctx context.Context, operatorID, userID int64, reason string, client *model.ClientInfo) (*model.LoginDataResponse, error) {
+	if operatorID == userID {
+		return nil, ErrCannotModifySelf
+	}
+
+	// 1. 检查操作人和目标用户
+	operator, err := s.userRepo.FindByID(operatorID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	if user.Status != 1 {
+		return nil, ErrUserDisabled
+	}
+
+	// 2. 不允许代登录管理员，避免管理员之间互相借用身份
+	roles, err := s.roleService.GetUserRoles(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		if role == model.RoleAdmin {
+			return nil, ErrImpersonateAdmin
+		}
+	}
+
+	// 3. 签发代登录令牌
+	sessionID := uuid.New().String()
+	uid := fmt.Sprintf("%d", user.ID)
+	actor := &jwt.Actor{UserID: fmt.Sprintf("%d", operator.ID), Username: operator.Username}
+	ttl := config.GetJWTImpersonationTTL()
+	accessToken, err := jwt.GenerateImpersonationToken(sessionID, uid, user.Username, roles, actor, ttl)
+	if err != nil {
+		return nil, err
+	}
+	accessClaims, err := jwt.ParseToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. 保存会话，到期自动失效，不影响用户其他会话的索引
+	if client == nil {
+		client = &model.ClientInfo{}
+	}
+	now := time.Now()
+	session := &model.Session{
+		ID:             sessionID,
+		UserID:         uid,
+		Device:         "管理员代登录: " + operator.Username,
+		IP:             client.IP,
+		UserAgent:      client.UserAgent,
+		ImpersonatorID: actor.UserID,
+		AccessTokenID:  accessClaims.ID,
+		CreatedAt:      now,
+		LastSeenAt:     now,
+	}
+	if err := s.sessionRepo.CreateTemporary(ctx, session, ttl, config.GetJWTRefreshTokenDuration()); err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, operatorID, audit.ActionImpersonate, audit.Target("user", user.ID), map[string]interface{}{
+		"reason":     reason,
+		"session_id": sessionID,
+		"expires_in": int64(ttl.Seconds()),
+	})
+
+	return &model.LoginDataResponse{
+		User:        user,
+		SessionID:   sessionID,
+		AccessToken: accessToken,
+		ExpiresIn:   int64(ttl.Seconds()),
+	}, nil
+}
+
+// Logout 退出登录，吊销当前会话的访问令牌和刷新令牌
+func (s *AuthService) Logout(ctx context.Context, userID int64, sessionID string) error {
+	if err := s.sessionRepo.Delete(ctx, fmt.Sprintf("%d", userID), sessionID); err != nil {
+		return err
+	}
+	audit.Record(ctx, userID, audit.ActionLogout, audit.Target("user", userID), map[string]interface{}{
+		"session_id": sessionID,
+	})
+	return nil
+}
+
+// RevokeSessions 吊销指定用户的全部会话（管理员操作）
+func (s *AuthService) RevokeSessions(ctx context.Context, operatorID, userID int64) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return ErrUserNotFound
+	}
+	if err := s.sessionRepo.DeleteAll(ctx, fmt.Sprintf("%d", userID)); err != nil {
+		return err
+	}
+	audit.Record(ctx, operatorID, audit.ActionSessionsRevoked, audit.Target("user", userID), nil)
+	return nil
+}
+
+// ListSessions 获取用户的全部登录会话，currentSessionID 对应的会话会被标记为当前会话
+func (s *AuthService) ListSessions(userID int64, currentSessionID string) ([]*model.Session, error) {
+	sessions, err := s.sessionRepo.List(context.Background(), fmt.Sprintf("%d", userID))
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		session.Current = session.ID == currentSessionID
+	}
+	return sessions, nil
+}
+
+// DeleteSession 结束用户的指定会话
+func (s *AuthService) DeleteSession(userID int64, sessionID string) error {
+	ctx := context.Background()
+	uid := fmt.Sprintf("%d", userID)
+	if _, err := s.sessionRepo.Find(ctx, uid, sessionID); err != nil {
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return s.sessionRepo.Delete(ctx, uid, sessionID)
+}
+
 // 注册用户
-func (s *AuthService) Register(req *model.RegisterRequest) (*model.User, error) {
+func (s *AuthService) Register(ctx context.Context, req *model.RegisterRequest) (*model.User, error) {
 	// 1. 检查用户名是否存在
 	exists, err := s.userRepo.ExistsByUsername(req.Username)
 	if err != nil {
@@ -102,19 +551,24 @@ func (s *AuthService) Register(req *model.RegisterRequest) (*model.User, error)
 		}
 	}
 
-	// 3. 加密密码
+	// 3. 检查密码策略
+	if err := checkPassword(req.Password, req.Username, req.Email); err != nil {
+		return nil, err
+	}
+
+	// 4. 加密密码
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. 生成雪花ID
+	// 5. 生成雪花ID
 	userID, err := snowflake.Generate()
 	if err != nil {
 		return nil, fmt.Errorf("生成用户ID失败: %w", err)
 	}
 
-	// 5. 创建用户
+	// 6. 创建用户
 	user := &model.User{
 		ID:       userID, // 使用雪花ID
 		Username: req.Username,
@@ -128,6 +582,18 @@ func (s *AuthService) Register(req *model.RegisterRequest) (*model.User, error)
 	if err := s.userRepo.Create(user); err != nil {
 		return nil, err
 	}
+	audit.Record(ctx, user.ID, audit.ActionRegister, audit.Target("user", user.ID), map[string]interface{}{
+		"username": user.Username,
+	})
+
+	// 7. 填写了邮箱时发送验证邮件，发送失败不影响注册，用户可稍后重新发送
+	if user.Email != "" {
+		go func() {
+			if err := NewAccountService().SendVerificationEmail(user.ID); err != nil {
+				logger.Warn("发送邮箱验证邮件失败", zap.Int64("userID", user.ID), zap.Error(err))
+			}
+		}()
+	}
 
 	return user, nil
 }
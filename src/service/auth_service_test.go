@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/utils/testutil"
+	"slices"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginTwoFactorFailuresCountTowardsLockout(t *testing.T) {
+	testutil.SetupConfig(t, func(cfg *config.AppConfig) {
+		cfg.Business.LoginMaxFailures = 3
+		cfg.Business.LoginFailureWindow = 600
+		cfg.Business.LoginLockoutDuration = 600
+		cfg.TwoFactor.ChallengeTTL = 300
+	})
+	testutil.SetupRedis(t)
+	db := testutil.SetupDB(t, &model.User{}, &model.UserTwoFactor{}, &model.AuditLog{})
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&model.User{ID: 1, Username: "alice", Password: string(hashed), Status: 1}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&model.UserTwoFactor{UserID: 1, Secret: "secret", Enabled: 1}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	s := NewAuthService()
+	client := &model.ClientInfo{IP: "203.0.113.1"}
+
+	// 每轮都用正确的密码重新登录、换一个新挑战，验证码错误仍然累计
+	var locked *LoginLockedError
+	for round := 1; round <= 3; round++ {
+		resp, err := s.Login(ctx, &model.LoginRequest{Username: "alice", Password: "password123"}, client)
+		if err != nil {
+			t.Fatalf("第 %d 轮登录失败: %v", round, err)
+		}
+		if !resp.TwoFactorRequired {
+			t.Fatalf("第 %d 轮未要求两步验证", round)
+		}
+
+		_, err = s.LoginTwoFactor(ctx, &model.LoginTwoFactorRequest{ChallengeToken: resp.ChallengeToken, Code: "WRONGCODE"}, client)
+		if round < 3 && !errors.Is(err, ErrTwoFactorInvalidCode) {
+			t.Fatalf("第 %d 轮 err = %v，期望 ErrTwoFactorInvalidCode", round, err)
+		}
+		if round == 3 && !errors.As(err, &locked) {
+			t.Fatalf("第 %d 轮 err = %v，期望 LoginLockedError", round, err)
+		}
+	}
+
+	// 锁定期间密码正确也不能登录
+	if _, err := s.Login(ctx, &model.LoginRequest{Username: "alice", Password: "password123"}, client); !errors.As(err, &locked) {
+		t.Fatalf("锁定后登录 err = %v，期望 LoginLockedError", err)
+	}
+}
+
+func TestEvictSessionsSkipsImpersonation(t *testing.T) {
+	testutil.SetupConfig(t, func(cfg *config.AppConfig) {
+		cfg.JWT.MaxSessionsPerUser = 2
+	})
+	testutil.SetupRedis(t)
+
+	ctx := context.Background()
+	s := NewAuthService()
+	base := time.Now().Add(-time.Hour)
+	create := func(id, impersonator string, offset time.Duration) {
+		session := &model.Session{ID: id, UserID: "1", ImpersonatorID: impersonator, CreatedAt: base.Add(offset), LastSeenAt: base}
+		if err := s.sessionRepo.Create(ctx, session, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 代登录会话最早创建，本人登录第三台设备时只在本人的会话中按上限淘汰
+	create("admin", "99", 0)
+	create("phone", "", time.Minute)
+	create("laptop", "", 2*time.Minute)
+	create("tablet", "", 3*time.Minute)
+
+	if err := s.evictSessions(ctx, "1"); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := s.sessionRepo.List(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, session := range sessions {
+		ids = append(ids, session.ID)
+	}
+	if !slices.Equal(ids, []string{"admin", "laptop", "tablet"}) {
+		t.Fatalf("剩余会话 = %v，期望 [admin laptop tablet]", ids)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/utils/cache"
+	"hi-go/src/utils/logger"
+	"hi-go/src/utils/redis"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// homeCacheVersionKey 首页缓存版本号，缓存键包含该版本号，递增后旧缓存全部失效（旧键随 TTL 过期）
+const homeCacheVersionKey = "home:cache:version"
+
+// 首页缓存类型
+const (
+	homeCacheList   = "list"   // 首页列表
+	homeCacheDetail = "detail" // 首页内容详情
+)
+
+// homeCacheGroup 合并同一缓存键的并发回源请求，缓存过期时只有一个请求查询数据库
+var homeCacheGroup singleflight.Group
+
+// homeCacheCounter 缓存命中统计（当前进程）
+type homeCacheCounter struct {
+	hits   atomic.Int64 // 命中次数
+	misses atomic.Int64 // 未命中次数
+	loads  atomic.Int64 // 实际查询数据库次数（并发未命中的请求合并为一次）
+}
+
+// homeCacheCounters 各类型缓存的命中统计
+var homeCacheCounters = map[string]*homeCacheCounter{
+	homeCacheList:   {},
+	homeCacheDetail: {},
+}
+
+// loadHomeCached 读取首页缓存（cache-aside），未命中时调用 load 查询并写入缓存
+// 缓存未开启或 Redis 不可用时直接调用 load
+func loadHomeCached[T any](ctx context.Context, c cache.Cache, kind, key string, load func() (*T, error)) (*T, error) {
+	ttl := config.GetHomeCacheTTL()
+	if ttl <= 0 || c == nil {
+		return load()
+	}
+
+	// 1. 读取版本号
+	version, err := redis.Get(ctx, homeCacheVersionKey)
+	if errors.Is(err, redis.ErrKeyNotFound) {
+		version = "0"
+	} else if err != nil {
+		logger.Warn("读取首页缓存版本号失败", zap.Error(err))
+		return load()
+	}
+	key = fmt.Sprintf("home:%s:v%s:%s", kind, version, key)
+	counter := homeCacheCounters[kind]
+
+	// 2. 读取缓存
+	if data, err := c.Get(ctx, key); err == nil {
+		var value T
+		if err := json.Unmarshal([]byte(data), &value); err == nil {
+			counter.hits.Add(1)
+			return &value, nil
+		}
+	}
+	counter.misses.Add(1)
+
+	// 3. 回源并写入缓存，同一键的并发请求共享结果
+	value, err, _ := homeCacheGroup.Do(key, func() (interface{}, error) {
+		counter.loads.Add(1)
+		result, err := load()
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(result)
+		if err == nil {
+			// 写缓存不受发起请求的客户端断开影响
+			err = c.Set(context.WithoutCancel(ctx), key, string(data), ttl)
+		}
+		if err != nil {
+			logger.Warn("写入首页缓存失败", zap.String("key", key), zap.Error(err))
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return value.(*T), nil
+}
+
+// invalidateHomeCache 使首页列表和详情缓存失效
+func invalidateHomeCache(ctx context.Context) {
+	if _, err := redis.Incr(context.WithoutCancel(ctx), homeCacheVersionKey); err != nil {
+		logger.Warn("首页缓存失效失败", zap.Error(err))
+	}
+}
+
+// homeCacheStats 获取缓存命中统计
+func homeCacheStats(kind string) model.HomeCacheCounter {
+	counter := homeCacheCounters[kind]
+	stats := model.HomeCacheCounter{
+		Hits:   counter.hits.Load(),
+		Misses: counter.misses.Load(),
+		Loads:  counter.loads.Load(),
+	}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
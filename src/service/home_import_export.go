@@ -0,0 +1,515 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/service/audit"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/xuri/excelize/v2"
+)
+
+// 导入导出文件格式
+const (
+	HomeFormatCSV  = "csv"
+	HomeFormatJSON = "json"
+	HomeFormatXLSX = "xlsx"
+)
+
+const (
+	homeTimeLayout       = "2006-01-02 15:04:05" // CSV/XLSX 中的时间格式（服务器本地时区）
+	homeImportBatchSize  = 200                   // 导入时每批写入的条数
+	homeExportBatchSize  = 500                   // 导出时每批读取的条数
+	homeExportSheetName  = "Sheet1"              // XLSX 导出的工作表名称
+	homeImportHeaderRows = 1                     // CSV/XLSX 的表头行数
+)
+
+// homeImportColumns 导入的列，表头不区分大小写，未知的列忽略（导出文件可直接导入）
+var homeImportColumns = []string{"title", "description", "image_url", "link", "sort", "status", "publish_at", "unpublish_at"}
+
+// homeExportColumns 导出的列
+var homeExportColumns = []string{"id", "title", "description", "image_url", "link", "sort", "status", "publish_at", "unpublish_at", "created_at"}
+
+var (
+	ErrHomeImportFormat   = errors.New("不支持的文件格式，仅支持 csv、json、xlsx")
+	ErrHomeImportParse    = errors.New("文件解析失败")
+	ErrHomeImportEmpty    = errors.New("文件中没有数据")
+	ErrHomeImportTooLarge = errors.New("导入行数超过上限")
+	ErrHomeImportInvalid  = errors.New("导入数据校验未通过，未写入任何数据")
+)
+
+// homeImportRow 解析后的一行导入数据
+type homeImportRow struct {
+	row    int                     // 行号
+	fields model.HomeFields        // 字段值
+	errors []model.HomeImportError // 解析阶段的错误（如数字、时间格式错误）
+}
+
+// Import 批量导入首页内容
+// 每行使用与 HomeUpdateRequest 相同的规则校验，任一行校验失败时不写入任何数据；dryRun 为 true 时只校验
+// 校验失败时返回 ErrHomeImportInvalid，同时返回包含逐行错误的结果
+func (s *HomeService) Import(ctx context.Context, r io.Reader, format string, dryRun bool, operatorID int64) (*model.HomeImportResult, error) {
+	// 1. 解析文件
+	rows, err := parseHomeImport(r, format)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrHomeImportEmpty
+	}
+	if maxRows := config.Config.Home.ImportMaxRows; maxRows > 0 && len(rows) > maxRows {
+		return nil, fmt.Errorf("%w：最多 %d 行，文件中有 %d 行", ErrHomeImportTooLarge, maxRows, len(rows))
+	}
+
+	// 2. 逐行校验
+	result := &model.HomeImportResult{
+		Total:  len(rows),
+		DryRun: dryRun,
+		Errors: []model.HomeImportError{},
+	}
+	homes := make([]model.Home, 0, len(rows))
+	for _, row := range rows {
+		if errs := validateHomeImportRow(row); len(errs) > 0 {
+			result.Errors = append(result.Errors, errs...)
+			continue
+		}
+		homes = append(homes, homeFromFields(&row.fields))
+	}
+	if len(result.Errors) > 0 {
+		return result, ErrHomeImportInvalid
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	// 3. 分批写入（同一事务）
+	if err := s.homeRepo.ImportBatch(homes, homeImportBatchSize); err != nil {
+		return nil, err
+	}
+	result.Imported = len(homes)
+	invalidateHomeCache(ctx)
+	s.searcher.Sync(homeIDs(homes)...)
+	audit.Record(ctx, operatorID, audit.ActionHomeImport, "", map[string]interface{}{
+		"format": format,
+		"count":  len(homes),
+	})
+
+	// 导入的内容可能带有发布期
+	rescheduleHomePublishWindows()
+	return result, nil
+}
+
+// Export 按条件导出首页内容，边查询边写入 w
+// w 实现 Flush() 时（如 gin.ResponseWriter）每批数据写完后立即发送给客户端
+func (s *HomeService) Export(ctx context.Context, req *model.HomeExportRequest, operatorID int64, w io.Writer) error {
+	if req.Format == "" {
+		req.Format = HomeFormatCSV
+	}
+
+	var count int
+	var err error
+	switch req.Format {
+	case HomeFormatCSV:
+		count, err = s.exportCSV(req, w)
+	case HomeFormatJSON:
+		count, err = s.exportJSON(req, w)
+	case HomeFormatXLSX:
+		count, err = s.exportXLSX(req, w)
+	default:
+		return ErrHomeImportFormat
+	}
+	if err != nil {
+		return err
+	}
+
+	audit.Record(ctx, operatorID, audit.ActionHomeExport, "", map[string]interface{}{
+		"format":  req.Format,
+		"keyword": req.Keyword,
+		"status":  req.Status,
+		"count":   count,
+	})
+	return nil
+}
+
+// exportCSV 导出为 CSV，带 UTF-8 BOM 以便 Excel 正确识别中文
+func (s *HomeService) exportCSV(req *model.HomeExportRequest, w io.Writer) (int, error) {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return 0, err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(homeExportColumns); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := s.homeRepo.ExportEach(req.Keyword, req.Status, homeExportBatchSize, func(homes []model.Home) error {
+		for i := range homes {
+			if err := cw.Write(homeExportRecord(&homes[i])); err != nil {
+				return err
+			}
+		}
+		count += len(homes)
+		cw.Flush()
+		flushWriter(w)
+		return cw.Error()
+	})
+	if err != nil {
+		return count, err
+	}
+	cw.Flush()
+	return count, cw.Error()
+}
+
+// exportJSON 导出为 JSON 数组，逐条编码
+func (s *HomeService) exportJSON(req *model.HomeExportRequest, w io.Writer) (int, error) {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := s.homeRepo.ExportEach(req.Keyword, req.Status, homeExportBatchSize, func(homes []model.Home) error {
+		for i := range homes {
+			data, err := json.Marshal(&homes[i])
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+			count++
+		}
+		flushWriter(w)
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	_, err = io.WriteString(w, "]")
+	return count, err
+}
+
+// exportXLSX 导出为 XLSX
+// 行数据通过流式写入器写入临时文件，最后一次性输出压缩包
+func (s *HomeService) exportXLSX(req *model.HomeExportRequest, w io.Writer) (int, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sw, err := f.NewStreamWriter(homeExportSheetName)
+	if err != nil {
+		return 0, err
+	}
+	if err := sw.SetRow("A1", toCells(homeExportColumns)); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = s.homeRepo.ExportEach(req.Keyword, req.Status, homeExportBatchSize, func(homes []model.Home) error {
+		for i := range homes {
+			cell, err := excelize.CoordinatesToCellName(1, count+homeImportHeaderRows+1)
+			if err != nil {
+				return err
+			}
+			if err := sw.SetRow(cell, toCells(homeExportRecord(&homes[i]))); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+	if err := sw.Flush(); err != nil {
+		return count, err
+	}
+
+	_, err = f.WriteTo(w)
+	return count, err
+}
+
+// parseHomeImport 按格式解析导入文件
+func parseHomeImport(r io.Reader, format string) ([]homeImportRow, error) {
+	var records [][]string
+	switch format {
+	case HomeFormatJSON:
+		return parseHomeImportJSON(r)
+	case HomeFormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		cr.TrimLeadingSpace = true
+		var err error
+		if records, err = cr.ReadAll(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrHomeImportParse, err)
+		}
+	case HomeFormatXLSX:
+		var err error
+		if records, err = readXLSXRecords(r); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrHomeImportParse, err)
+		}
+	default:
+		return nil, ErrHomeImportFormat
+	}
+	return parseHomeImportRecords(records)
+}
+
+// parseHomeImportJSON 解析 JSON 数组，每个元素的字段与 HomeUpdateRequest 相同
+func parseHomeImportJSON(r io.Reader) ([]homeImportRow, error) {
+	var items []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHomeImportParse, err)
+	}
+
+	rows := make([]homeImportRow, 0, len(items))
+	for i, item := range items {
+		row := homeImportRow{row: i + 1}
+		if err := json.Unmarshal(item, &row.fields); err != nil {
+			row.errors = append(row.errors, model.HomeImportError{Row: row.row, Message: "数据格式错误: " + err.Error()})
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// parseHomeImportRecords 解析 CSV/XLSX 的表格数据，第一行为表头，空行跳过
+func parseHomeImportRecords(records [][]string) ([]homeImportRow, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	// 1. 表头映射到列下标
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		columns[name] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, fmt.Errorf("%w: 表头缺少 title 列，可用的列：%s", ErrHomeImportParse, strings.Join(homeImportColumns, ", "))
+	}
+
+	// 2. 逐行读取
+	rows := make([]homeImportRow, 0, len(records)-1)
+	for i, record := range records[homeImportHeaderRows:] {
+		if isBlankRecord(record) {
+			continue
+		}
+		get := func(column string) string {
+			if idx, ok := columns[column]; ok && idx < len(record) {
+				return strings.TrimSpace(record[idx])
+			}
+			return ""
+		}
+
+		row := homeImportRow{row: i + homeImportHeaderRows + 1}
+		row.fields.Title = get("title")
+		row.fields.Description = get("description")
+		row.fields.ImageURL = get("image_url")
+		row.fields.Link = get("link")
+		for _, column := range []string{"sort", "status"} {
+			value := get(column)
+			if value == "" {
+				continue
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				row.errors = append(row.errors, model.HomeImportError{Row: row.row, Field: column, Message: "必须是整数"})
+				continue
+			}
+			if column == "sort" {
+				row.fields.Sort = &n
+			} else {
+				row.fields.Status = &n
+			}
+		}
+		for _, column := range []string{"publish_at", "unpublish_at"} {
+			value := get(column)
+			if value == "" {
+				continue
+			}
+			t, err := parseHomeImportTime(value)
+			if err != nil {
+				row.errors = append(row.errors, model.HomeImportError{Row: row.row, Field: column, Message: "时间格式错误，应为 " + homeTimeLayout})
+				continue
+			}
+			if column == "publish_at" {
+				row.fields.PublishAt = &t
+			} else {
+				row.fields.UnpublishAt = &t
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// readXLSXRecords 读取 XLSX 第一个工作表的全部行（原始单元格值）
+func readXLSXRecords(r io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, nil
+	}
+	return f.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+}
+
+// parseHomeImportTime 解析导入文件中的时间
+// 支持 2006-01-02 15:04:05、2006-01-02 15:04、RFC3339，以及 XLSX 日期单元格的序列值
+func parseHomeImportTime(value string) (time.Time, error) {
+	for _, layout := range []string{homeTimeLayout, "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if serial, err := strconv.ParseFloat(value, 64); err == nil {
+		t, err := excelize.ExcelDateToTime(serial, false)
+		if err != nil {
+			return time.Time{}, err
+		}
+		// 序列值不含时区，按服务器本地时间解释
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local), nil
+	}
+	return time.Time{}, fmt.Errorf("无法解析时间: %s", value)
+}
+
+// validateHomeImportRow 校验一行导入数据：解析错误、HomeUpdateRequest 的字段规则、标题必填、发布期
+func validateHomeImportRow(row homeImportRow) []model.HomeImportError {
+	errs := row.errors
+	if err := binding.Validator.ValidateStruct(&row.fields); err != nil {
+		var fieldErrs validator.ValidationErrors
+		if !errors.As(err, &fieldErrs) {
+			return append(errs, model.HomeImportError{Row: row.row, Message: err.Error()})
+		}
+		for _, fe := range fieldErrs {
+			errs = append(errs, model.HomeImportError{
+				Row:     row.row,
+				Field:   homeFieldName(fe.StructField()),
+				Message: validationMessage(fe),
+			})
+		}
+	}
+	if strings.TrimSpace(row.fields.Title) == "" {
+		errs = append(errs, model.HomeImportError{Row: row.row, Field: "title", Message: "标题不能为空"})
+	}
+	if row.fields.PublishAt != nil && row.fields.UnpublishAt != nil && !row.fields.UnpublishAt.After(*row.fields.PublishAt) {
+		errs = append(errs, model.HomeImportError{Row: row.row, Field: "unpublish_at", Message: ErrHomeInvalidWindow.Error()})
+	}
+	return errs
+}
+
+// homeFromFields 根据导入的字段创建首页内容，未填写的状态默认为启用
+func homeFromFields(fields *model.HomeFields) model.Home {
+	home := model.Home{
+		Title:       fields.Title,
+		Description: fields.Description,
+		ImageURL:    fields.ImageURL,
+		Link:        fields.Link,
+		Status:      1,
+		PublishAt:   fields.PublishAt,
+		UnpublishAt: fields.UnpublishAt,
+	}
+	if fields.Sort != nil {
+		home.Sort = *fields.Sort
+	}
+	if fields.Status != nil {
+		home.Status = *fields.Status
+	}
+	return home
+}
+
+// homeExportRecord 首页内容转换为导出的一行，顺序与 homeExportColumns 一致
+func homeExportRecord(home *model.Home) []string {
+	return []string{
+		strconv.FormatInt(home.ID, 10),
+		home.Title,
+		home.Description,
+		home.ImageURL,
+		home.Link,
+		strconv.Itoa(home.Sort),
+		strconv.Itoa(home.Status),
+		formatHomeTime(home.PublishAt),
+		formatHomeTime(home.UnpublishAt),
+		home.CreatedAt.Format(homeTimeLayout),
+	}
+}
+
+// formatHomeTime 格式化可为空的时间
+func formatHomeTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Local().Format(homeTimeLayout)
+}
+
+// homeFieldName 根据结构体字段名获取 HomeFields 的 JSON 字段名
+func homeFieldName(structField string) string {
+	if f, ok := reflect.TypeOf(model.HomeFields{}).FieldByName(structField); ok {
+		if name, _, _ := strings.Cut(f.Tag.Get("json"), ","); name != "" {
+			return name
+		}
+	}
+	return structField
+}
+
+// validationMessage 字段校验失败的提示信息
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "max":
+		return fmt.Sprintf("长度不能超过 %s", fe.Param())
+	case "min":
+		return fmt.Sprintf("不能小于 %s", fe.Param())
+	case "oneof":
+		return fmt.Sprintf("只能是 %s 之一", fe.Param())
+	default:
+		return "不满足校验规则 " + fe.Tag()
+	}
+}
+
+// isBlankRecord 是否为空行
+func isBlankRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// toCells 字符串切片转换为 XLSX 单元格值
+func toCells(values []string) []interface{} {
+	cells := make([]interface{}, len(values))
+	for i, v := range values {
+		cells[i] = v
+	}
+	return cells
+}
+
+// flushWriter w 支持 Flush 时立即发送已写入的数据
+func flushWriter(w io.Writer) {
+	if f, ok := w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+}
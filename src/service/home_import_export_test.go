@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"hi-go/src/model"
+	"hi-go/src/utils/testutil"
+	"strings"
+	"testing"
+)
+
+func TestHomeImportKeepsDisabledStatus(t *testing.T) {
+	testutil.SetupConfig(t, nil)
+	testutil.SetupRedis(t)
+	db := testutil.SetupDB(t, &model.Home{}, &model.HomeTag{}, &model.AuditLog{})
+
+	csv := "title,status\n已禁用,0\n已启用,1\n未填写状态,\n"
+	result, err := NewHomeService().Import(context.Background(), strings.NewReader(csv), HomeFormatCSV, false, 1)
+	if err != nil {
+		t.Fatalf("导入失败: %v (%+v)", err, result)
+	}
+	if result.Imported != 3 {
+		t.Fatalf("Imported = %d，期望 3", result.Imported)
+	}
+
+	want := map[string]int{"已禁用": 0, "已启用": 1, "未填写状态": 1}
+	var homes []model.Home
+	if err := db.Find(&homes).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, home := range homes {
+		if home.Status != want[home.Title] {
+			t.Errorf("%s 的状态为 %d，期望 %d", home.Title, home.Status, want[home.Title])
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"hi-go/src/config"
+	"hi-go/src/repository"
+	"hi-go/src/utils/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// homePublishWake 发布期变化时唤醒调度任务重新计算下一次上下线时间
+var homePublishWake = make(chan struct{}, 1)
+
+// StartHomePublishScheduler 启动定时上下线任务
+// 在最近一次开始或结束展示的时间点使首页列表缓存失效；
+// 每隔 publish_check_interval 也会重新计算一次，以感知其他实例修改的发布期。ctx 取消后任务退出
+func StartHomePublishScheduler(ctx context.Context) {
+	interval := config.GetHomePublishCheckInterval()
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	homeRepo := repository.NewHomeRepository()
+	go func() {
+		for {
+			now := time.Now()
+			wait := interval
+			next, err := homeRepo.NextWindowChange(now)
+			if err != nil {
+				logger.Warn("查询首页下一次上下线时间失败", zap.Error(err))
+			} else if next != nil && next.Sub(now) < wait {
+				wait = next.Sub(now)
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-homePublishWake:
+				timer.Stop()
+			case <-timer.C:
+				if next != nil && !time.Now().Before(*next) {
+					logger.Info("首页内容定时上下线", zap.Time("at", *next))
+					invalidateHomeCache(ctx)
+				}
+			}
+		}
+	}()
+	logger.Info("首页定时上下线任务已启动", zap.Duration("checkInterval", interval))
+}
+
+// rescheduleHomePublishWindows 通知调度任务重新计算下一次上下线时间
+func rescheduleHomePublishWindows() {
+	select {
+	case homePublishWake <- struct{}{}:
+	default:
+	}
+}
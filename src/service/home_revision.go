@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/service/audit"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrHomeRevisionNotFound 修订记录不存在或不属于该内容
+var ErrHomeRevisionNotFound = errors.New("修订记录不存在")
+
+// Revisions 获取首页内容的修订记录（最新的在前）
+// 内容从未修改过时列表为空，第一次修改时会同时保存修改前的原始内容
+func (s *HomeService) Revisions(homeID int64, req *model.HomeRevisionListRequest) (*model.HomeRevisionListDataResponse, error) {
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = config.Config.Business.DefaultPageSize
+	}
+	if req.PageSize > config.Config.Business.MaxPageSize {
+		req.PageSize = config.Config.Business.MaxPageSize
+	}
+
+	if _, err := s.findHome(homeID); err != nil {
+		return nil, err
+	}
+
+	list, total, err := s.revisionRepo.List(homeID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &model.HomeRevisionListDataResponse{
+		List:  list,
+		Total: total,
+	}, nil
+}
+
+// DiffRevisions 比较同一内容的两个修订记录，返回有变化的字段
+func (s *HomeService) DiffRevisions(homeID int64, req *model.HomeRevisionDiffRequest) (*model.HomeRevisionDiffResponse, error) {
+	from, err := s.findRevision(homeID, req.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.findRevision(homeID, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diffHomeSnapshots(&from.Snapshot, &to.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return &model.HomeRevisionDiffResponse{
+		From:    *from,
+		To:      *to,
+		Changes: changes,
+	}, nil
+}
+
+// Rollback 将内容恢复为指定修订记录的快照，并保存一条新的修订记录
+// 快照中的版位已被删除时恢复为未分配版位
+func (s *HomeService) Rollback(ctx context.Context, homeID int64, req *model.HomeRollbackRequest, operatorID int64) (*model.Home, error) {
+	// 1. 检查内容和修订记录
+	if _, err := s.findHome(homeID); err != nil {
+		return nil, err
+	}
+	target, err := s.findRevision(homeID, req.RevisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. 用快照覆盖全部可编辑字段
+	snapshot := target.Snapshot
+	updates := map[string]interface{}{
+		"title":        snapshot.Title,
+		"description":  snapshot.Description,
+		"image_url":    snapshot.ImageURL,
+		"link":         snapshot.Link,
+		"sort":         snapshot.Sort,
+		"status":       snapshot.Status,
+		"publish_at":   snapshot.PublishAt,
+		"unpublish_at": snapshot.UnpublishAt,
+		"slot_id":      nil,
+	}
+	if snapshot.SlotID != nil {
+		if _, err := s.slotRepo.FindByID(*snapshot.SlotID); err == nil {
+			updates["slot_id"] = *snapshot.SlotID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	tags := normalizeTags(snapshot.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	// 3. 执行更新并保存修订记录
+	revision := &model.HomeRevision{
+		AuthorID:     operatorID,
+		Action:       model.HomeRevisionRollback,
+		RollbackFrom: &target.ID,
+	}
+	home, err := s.homeRepo.Update(homeID, updates, tags, revision)
+	if err != nil {
+		return nil, err
+	}
+	invalidateHomeCache(ctx)
+	s.searcher.Sync(homeID)
+	rescheduleHomePublishWindows()
+	audit.Record(ctx, operatorID, audit.ActionHomeRollback, audit.Target("home", homeID), map[string]interface{}{
+		"revision_id": target.ID,
+		"version":     target.Version,
+	})
+	return home, nil
+}
+
+// findHome 根据ID查找首页内容，不存在时返回 ErrHomeNotFound
+func (s *HomeService) findHome(id int64) (*model.Home, error) {
+	home, err := s.homeRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrHomeNotFound
+	}
+	return home, err
+}
+
+// findRevision 查找属于该内容的修订记录，不存在时返回 ErrHomeRevisionNotFound
+func (s *HomeService) findRevision(homeID, id int64) (*model.HomeRevision, error) {
+	revision, err := s.revisionRepo.FindByID(homeID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrHomeRevisionNotFound
+	}
+	return revision, err
+}
+
+// diffHomeSnapshots 按字段比较两个快照，字段值以 JSON 表示
+func diffHomeSnapshots(from, to *model.HomeSnapshot) ([]model.HomeRevisionChange, error) {
+	changes := []model.HomeRevisionChange{}
+	fromValue, toValue := reflect.ValueOf(from).Elem(), reflect.ValueOf(to).Elem()
+	for i := 0; i < fromValue.NumField(); i++ {
+		oldJSON, err := json.Marshal(fromValue.Field(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		newJSON, err := json.Marshal(toValue.Field(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if bytes.Equal(oldJSON, newJSON) {
+			continue
+		}
+
+		field, _, _ := strings.Cut(fromValue.Type().Field(i).Tag.Get("json"), ",")
+		changes = append(changes, model.HomeRevisionChange{Field: field, From: oldJSON, To: newJSON})
+	}
+	return changes, nil
+}
@@ -0,0 +1,384 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/utils/elasticsearch"
+	"hi-go/src/utils/logger"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+	"go.uber.org/zap"
+)
+
+// 检索引擎，用于标识搜索结果的来源
+const (
+	SearchEngineElasticsearch = "elasticsearch"
+	SearchEngineMySQL         = "mysql"
+)
+
+const (
+	homeSearchReindexBatch = 500              // 重建索引时每批写入的条数
+	homeSearchSyncTimeout  = 10 * time.Second // 同步单次变更的超时时间
+)
+
+// HomeSearcher 首页内容全文检索（Elasticsearch）
+// 索引通过别名访问，重建索引时写入新索引后原子切换别名，期间搜索不受影响。
+// 索引包含全部未删除的内容（含禁用和不在展示时间内的），状态和展示时间在查询时过滤
+type HomeSearcher struct {
+	homeRepo *repository.HomeRepository
+}
+
+// NewHomeSearcher 创建首页内容检索实例
+func NewHomeSearcher() *HomeSearcher {
+	return &HomeSearcher{
+		homeRepo: repository.NewHomeRepository(),
+	}
+}
+
+// Enabled 是否启用 ES 检索
+func (s *HomeSearcher) Enabled() bool {
+	return config.Config.Search.Enabled && elasticsearch.Client != nil
+}
+
+// alias 索引别名
+func (s *HomeSearcher) alias() string {
+	return config.Config.Search.HomeIndex
+}
+
+// EnsureIndex 索引别名不存在时创建一个空索引并绑定别名
+// 已有数据需要执行 reindex-home 命令写入
+func (s *HomeSearcher) EnsureIndex(ctx context.Context) error {
+	err := elasticsearch.Do(ctx, esapi.IndicesGetAliasRequest{Name: []string{s.alias()}}, nil)
+	if err == nil {
+		return nil
+	}
+	if !elasticsearch.IsNotFound(err) {
+		return err
+	}
+
+	index, err := s.createIndex(ctx)
+	if err != nil {
+		return err
+	}
+	return s.switchAlias(ctx, index)
+}
+
+// Search 全文检索处于展示时间内的启用内容，按相关度、排序、ID 排列，并返回高亮片段
+// filter 中只使用版位和标签条件
+func (s *HomeSearcher) Search(ctx context.Context, keyword string, at time.Time, filter repository.HomeFilter, page, pageSize int) ([]model.HomeSearchItem, int64, error) {
+	if timeout := config.GetSearchTimeout(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	now := at.Format(time.RFC3339)
+	filters := []interface{}{
+		map[string]interface{}{"term": map[string]interface{}{"status": 1}},
+		openEndedRange("publish_at", "lte", now),
+		openEndedRange("unpublish_at", "gt", now),
+	}
+	if filter.SlotID > 0 {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"slot_id": filter.SlotID}})
+	}
+	if filter.Tag != "" {
+		filters = append(filters, map[string]interface{}{"term": map[string]interface{}{"tags.name": filter.Tag}})
+	}
+	query := map[string]interface{}{
+		"from":             (page - 1) * pageSize,
+		"size":             pageSize,
+		"track_total_hits": true,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": []interface{}{
+					map[string]interface{}{
+						"multi_match": map[string]interface{}{
+							"query":                keyword,
+							"fields":               []string{"title^3", "description"},
+							"minimum_should_match": "75%",
+						},
+					},
+				},
+				"filter": filters,
+			},
+		},
+		"sort": []interface{}{
+			"_score",
+			map[string]interface{}{"sort": "asc"},
+			map[string]interface{}{"id": "desc"},
+		},
+		// 高亮片段中的原文先做 HTML 转义，只有 <em> 是服务端加的标签，客户端可以直接按 HTML 渲染
+		"highlight": map[string]interface{}{
+			"encoder":   "html",
+			"pre_tags":  []string{"<em>"},
+			"post_tags": []string{"</em>"},
+			"fields": map[string]interface{}{
+				"title":       map[string]interface{}{"number_of_fragments": 0},
+				"description": map[string]interface{}{"fragment_size": 100, "number_of_fragments": 1},
+			},
+		},
+	}
+	body, err := elasticsearch.JSONBody(query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var result struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source    model.Home          `json:"_source"`
+				Highlight map[string][]string `json:"highlight"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	req := esapi.SearchRequest{Index: []string{s.alias()}, Body: body}
+	if err := elasticsearch.Do(ctx, req, &result); err != nil {
+		return nil, 0, err
+	}
+
+	list := make([]model.HomeSearchItem, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		item := model.HomeSearchItem{Home: hit.Source}
+		if len(hit.Highlight) > 0 {
+			item.Highlight = make(map[string]string, len(hit.Highlight))
+			for field, fragments := range hit.Highlight {
+				item.Highlight[field] = strings.Join(fragments, "...")
+			}
+		}
+		list = append(list, item)
+	}
+	return list, result.Hits.Total.Value, nil
+}
+
+// Sync 按数据库中的最新数据同步指定内容：存在则写入索引，已删除则从索引中移除
+// 在后台执行，失败只记录日志，可通过 reindex-home 命令修复
+func (s *HomeSearcher) Sync(ids ...int64) {
+	if !s.Enabled() || len(ids) == 0 {
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), homeSearchSyncTimeout)
+		defer cancel()
+
+		homes, err := s.homeRepo.FindByIDs(ids)
+		if err != nil {
+			logger.Warn("同步首页搜索索引失败", zap.Int64s("ids", ids), zap.Error(err))
+			return
+		}
+		found := make(map[int64]bool, len(homes))
+		for _, home := range homes {
+			found[home.ID] = true
+		}
+		var deleted []int64
+		for _, id := range ids {
+			if !found[id] {
+				deleted = append(deleted, id)
+			}
+		}
+
+		if err := s.bulk(ctx, s.alias(), homes, deleted); err != nil {
+			logger.Warn("同步首页搜索索引失败", zap.Int64s("ids", ids), zap.Error(err))
+		}
+	}()
+}
+
+// Reindex 重建索引：创建新索引并写入全部未删除的内容，完成后切换别名并删除旧索引
+func (s *HomeSearcher) Reindex(ctx context.Context) (int, error) {
+	if elasticsearch.Client == nil {
+		return 0, elasticsearch.ErrNotInitialized
+	}
+
+	// 1. 创建新索引
+	index, err := s.createIndex(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// 2. 分批写入
+	count := 0
+	err = s.homeRepo.ExportEach("", nil, homeSearchReindexBatch, func(homes []model.Home) error {
+		if err := s.bulk(ctx, index, homes, nil); err != nil {
+			return err
+		}
+		count += len(homes)
+		return nil
+	})
+	if err != nil {
+		_ = elasticsearch.Do(ctx, esapi.IndicesDeleteRequest{Index: []string{index}}, nil)
+		return count, err
+	}
+
+	// 3. 刷新后切换别名
+	if err := elasticsearch.Do(ctx, esapi.IndicesRefreshRequest{Index: []string{index}}, nil); err != nil {
+		return count, err
+	}
+	return count, s.switchAlias(ctx, index)
+}
+
+// createIndex 创建带时间后缀的新索引，返回索引名
+func (s *HomeSearcher) createIndex(ctx context.Context) (string, error) {
+	index := fmt.Sprintf("%s_%s", s.alias(), time.Now().Format("20060102150405"))
+	body, err := elasticsearch.JSONBody(homeIndexDefinition(config.Config.Search.Analyzer))
+	if err != nil {
+		return "", err
+	}
+	if err := elasticsearch.Do(ctx, esapi.IndicesCreateRequest{Index: index, Body: body}, nil); err != nil {
+		return "", fmt.Errorf("创建索引 %s 失败: %w", index, err)
+	}
+	return index, nil
+}
+
+// switchAlias 将别名原子切换到 index，并删除别名原先指向的索引
+func (s *HomeSearcher) switchAlias(ctx context.Context, index string) error {
+	var current map[string]interface{}
+	err := elasticsearch.Do(ctx, esapi.IndicesGetAliasRequest{Name: []string{s.alias()}}, &current)
+	if err != nil && !elasticsearch.IsNotFound(err) {
+		return err
+	}
+
+	actions := []interface{}{
+		map[string]interface{}{"add": map[string]interface{}{"index": index, "alias": s.alias()}},
+	}
+	for old := range current {
+		if old != index {
+			actions = append(actions, map[string]interface{}{"remove_index": map[string]interface{}{"index": old}})
+		}
+	}
+	body, err := elasticsearch.JSONBody(map[string]interface{}{"actions": actions})
+	if err != nil {
+		return err
+	}
+	return elasticsearch.Do(ctx, esapi.IndicesUpdateAliasesRequest{Body: body}, nil)
+}
+
+// bulk 批量写入和删除文档
+func (s *HomeSearcher) bulk(ctx context.Context, index string, homes []model.Home, deleted []int64) error {
+	if len(homes) == 0 && len(deleted) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for i := range homes {
+		meta := map[string]interface{}{"index": map[string]interface{}{"_id": strconv.FormatInt(homes[i].ID, 10)}}
+		if err := enc.Encode(meta); err != nil {
+			return err
+		}
+		if err := enc.Encode(&homes[i]); err != nil {
+			return err
+		}
+	}
+	for _, id := range deleted {
+		meta := map[string]interface{}{"delete": map[string]interface{}{"_id": strconv.FormatInt(id, 10)}}
+		if err := enc.Encode(meta); err != nil {
+			return err
+		}
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int             `json:"status"`
+			Error  json.RawMessage `json:"error"`
+		} `json:"items"`
+	}
+	if err := elasticsearch.Do(ctx, esapi.BulkRequest{Index: index, Body: &buf}, &result); err != nil {
+		return err
+	}
+	if result.Errors {
+		for _, item := range result.Items {
+			for action, r := range item {
+				// 删除不存在的文档不视为错误
+				if r.Error != nil && !(action == "delete" && r.Status == 404) {
+					return fmt.Errorf("批量写入索引失败: %s", r.Error)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// openEndedRange 可为空的时间范围过滤：字段为空（不限制）或满足范围条件
+func openEndedRange(field, op, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"bool": map[string]interface{}{
+			"minimum_should_match": 1,
+			"should": []interface{}{
+				map[string]interface{}{"bool": map[string]interface{}{
+					"must_not": map[string]interface{}{"exists": map[string]interface{}{"field": field}},
+				}},
+				map[string]interface{}{"range": map[string]interface{}{field: map[string]interface{}{op: value}}},
+			},
+		},
+	}
+}
+
+// homeIndexDefinition 首页内容索引的设置和映射
+// ngram：单字和双字切分，无需插件，适合中文短文本；ik：使用 IK 分词插件，写入时细粒度切分、查询时智能切分
+func homeIndexDefinition(analyzer string) map[string]interface{} {
+	indexAnalyzer, searchAnalyzer := "home_ngram", "home_ngram"
+	settings := map[string]interface{}{
+		"analysis": map[string]interface{}{
+			"tokenizer": map[string]interface{}{
+				"home_ngram": map[string]interface{}{
+					"type":        "ngram",
+					"min_gram":    1,
+					"max_gram":    2,
+					"token_chars": []string{"letter", "digit"},
+				},
+			},
+			"analyzer": map[string]interface{}{
+				"home_ngram": map[string]interface{}{
+					"type":      "custom",
+					"tokenizer": "home_ngram",
+					"filter":    []string{"lowercase"},
+				},
+			},
+		},
+	}
+	if analyzer == "ik" {
+		indexAnalyzer, searchAnalyzer = "ik_max_word", "ik_smart"
+	}
+
+	text := map[string]interface{}{"type": "text", "analyzer": indexAnalyzer, "search_analyzer": searchAnalyzer}
+	stored := map[string]interface{}{"type": "keyword", "index": false}
+	return map[string]interface{}{
+		"settings": settings,
+		"mappings": map[string]interface{}{
+			"dynamic": false,
+			"properties": map[string]interface{}{
+				"id":           map[string]interface{}{"type": "long"},
+				"title":        text,
+				"description":  text,
+				"image_url":    stored,
+				"link":         stored,
+				"sort":         map[string]interface{}{"type": "integer"},
+				"status":       map[string]interface{}{"type": "byte"},
+				"publish_at":   map[string]interface{}{"type": "date"},
+				"unpublish_at": map[string]interface{}{"type": "date"},
+				"version":      map[string]interface{}{"type": "integer"},
+				"slot_id":      map[string]interface{}{"type": "long"},
+				"tags": map[string]interface{}{
+					"properties": map[string]interface{}{
+						"id":   map[string]interface{}{"type": "long"},
+						"name": map[string]interface{}{"type": "keyword"},
+					},
+				},
+				"created_at": map[string]interface{}{"type": "date"},
+				"updated_at": map[string]interface{}{"type": "date"},
+			},
+		},
+	}
+}
@@ -1,26 +1,113 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"hi-go/src/config"
 	"hi-go/src/model"
 	"hi-go/src/repository"
+	"hi-go/src/service/audit"
+	"hi-go/src/service/webhook"
+	"hi-go/src/utils/cache"
+	"hi-go/src/utils/logger"
+	"hi-go/src/utils/redis"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrHomeNotInTrash    = errors.New("回收站中不存在该首页内容")
+	ErrHomeInvalidWindow = errors.New("结束展示时间必须晚于开始展示时间")
+	ErrHomeNotFound      = errors.New("首页内容不存在")
+	ErrHomeDuplicateID   = errors.New("排序列表中存在重复的ID")
+	ErrHomeConflict      = errors.New("内容已被他人修改，请刷新后重试")
 )
 
 // 首页业务逻辑层
 type HomeService struct {
-	homeRepo *repository.HomeRepository
+	homeRepo     *repository.HomeRepository
+	slotRepo     *repository.HomeSlotRepository
+	revisionRepo *repository.HomeRevisionRepository
+	searcher     *HomeSearcher
+	cache        cache.Cache
 }
 
 // 创建首页服务实例
 func NewHomeService() *HomeService {
 	return &HomeService{
-		homeRepo: repository.NewHomeRepository(),
+		homeRepo:     repository.NewHomeRepository(),
+		slotRepo:     repository.NewHomeSlotRepository(),
+		revisionRepo: repository.NewHomeRevisionRepository(),
+		searcher:     NewHomeSearcher(),
+		cache:        newHomeCache(),
+	}
+}
+
+// newHomeCache 创建首页缓存，Redis 未初始化时不使用缓存
+func newHomeCache() cache.Cache {
+	if redis.Client == nil {
+		return nil
+	}
+	return cache.NewRedisCache(redis.Client)
+}
+
+// 获取首页列表（优先读取缓存）
+func (s *HomeService) GetList(ctx context.Context, req *model.HomeListRequest) (*model.HomeListDataResponse, error) {
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = config.Config.Business.DefaultPageSize
+	}
+	if req.PageSize > config.Config.Business.MaxPageSize {
+		req.PageSize = config.Config.Business.MaxPageSize
+	}
+
+	key := url.Values{
+		"slot":      {req.Slot},
+		"tag":       {req.Tag},
+		"page":      {strconv.Itoa(req.Page)},
+		"page_size": {strconv.Itoa(req.PageSize)},
+	}.Encode()
+	return loadHomeCached(ctx, s.cache, homeCacheList, key, func() (*model.HomeListDataResponse, error) {
+		return s.list(req)
+	})
+}
+
+// list 查询首页列表
+func (s *HomeService) list(req *model.HomeListRequest) (*model.HomeListDataResponse, error) {
+	// 按版位、标签筛选，版位已禁用时返回空列表
+	filter, enabled, err := s.homeFilter(req.Slot, req.Tag)
+	if err != nil {
+		return nil, err
 	}
+	if !enabled {
+		return &model.HomeListDataResponse{List: []model.Home{}}, nil
+	}
+
+	// 查询数据
+	list, total, err := s.homeRepo.List(time.Now(), false, filter, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.HomeListDataResponse{
+		List:  list,
+		Total: total,
+	}, nil
 }
 
-// 获取首页列表
-func (s *HomeService) GetList(req *model.HomeListRequest) (*model.HomeListDataResponse, error) {
+// Preview 预览首页列表，包含尚未到开始展示时间的内容
+// 指定 at 时返回该时间点用户将看到的列表
+func (s *HomeService) Preview(req *model.HomePreviewRequest) (*model.HomeListDataResponse, error) {
 	// 设置默认分页参数
 	if req.Page <= 0 {
 		req.Page = 1
@@ -33,7 +120,11 @@ func (s *HomeService) GetList(req *model.HomeListRequest) (*model.HomeListDataRe
 	}
 
 	// 查询数据
-	list, total, err := s.homeRepo.List(req.Page, req.PageSize)
+	at, includeUpcoming := time.Now(), true
+	if req.At != nil {
+		at, includeUpcoming = *req.At, false
+	}
+	list, total, err := s.homeRepo.List(at, includeUpcoming, repository.HomeFilter{}, req.Page, req.PageSize)
 	if err != nil {
 		return nil, err
 	}
@@ -45,7 +136,7 @@ func (s *HomeService) GetList(req *model.HomeListRequest) (*model.HomeListDataRe
 }
 
 // 创建模拟数据（30条）
-func (s *HomeService) CreateMockData() error {
+func (s *HomeService) CreateMockData(ctx context.Context, operatorID int64) error {
 	// 生成30条模拟数据
 	homes := make([]model.Home, 30)
 	for i := 0; i < 30; i++ {
@@ -60,13 +151,21 @@ func (s *HomeService) CreateMockData() error {
 	}
 
 	// 批量插入数据库
-	return s.homeRepo.BatchCreate(homes)
+	if err := s.homeRepo.BatchCreate(homes); err != nil {
+		return err
+	}
+	invalidateHomeCache(ctx)
+	s.searcher.Sync(homeIDs(homes)...)
+	audit.Record(ctx, operatorID, audit.ActionHomeMock, "", map[string]interface{}{
+		"count": len(homes),
+	})
+	return nil
 }
 
 // Update 更新首页内容
-func (s *HomeService) Update(req *model.HomeUpdateRequest) error {
+func (s *HomeService) Update(ctx context.Context, req *model.HomeUpdateRequest, operatorID int64) error {
 	// 1. 检查记录是否存在
-	_, err := s.homeRepo.FindByID(req.ID)
+	home, err := s.homeRepo.FindByID(req.ID)
 	if err != nil {
 		return fmt.Errorf("首页内容不存在")
 	}
@@ -91,29 +190,177 @@ func (s *HomeService) Update(req *model.HomeUpdateRequest) error {
 	if req.Status != nil {
 		updates["status"] = *req.Status
 	}
+	windowChanged, err := applyPublishWindow(home, req, updates)
+	if err != nil {
+		return err
+	}
+	if err := s.applySlot(home, req.SlotID, updates); err != nil {
+		return err
+	}
+	tags := normalizeTags(req.Tags)
 
 	// 3. 执行更新
-	if len(updates) == 0 {
+	if len(updates) == 0 && tags == nil {
 		return fmt.Errorf("没有需要更新的字段")
 	}
 
-	return s.homeRepo.Update(req.ID, updates)
+	// 同一事务中保存修订记录
+	revision := &model.HomeRevision{AuthorID: operatorID, Action: model.HomeRevisionUpdate}
+	if _, err := s.homeRepo.Update(req.ID, updates, tags, revision); err != nil {
+		return err
+	}
+	invalidateHomeCache(ctx)
+	s.searcher.Sync(req.ID)
+	if tags != nil {
+		updates["tags"] = tags // 仅用于审计日志
+	}
+	audit.Record(ctx, operatorID, audit.ActionHomeUpdate, audit.Target("home", req.ID), updates)
+	publishHomeEvent(ctx, webhook.EventHomeUpdated, &webhook.HomeEvent{
+		ID:         req.ID,
+		Title:      home.Title,
+		OperatorID: operatorID,
+		Changes:    updates,
+	})
+
+	// 4. 发布期或状态变化后重新计算下一次上下线时间
+	if windowChanged || req.Status != nil {
+		rescheduleHomePublishWindows()
+	}
+	return nil
+}
+
+// applyPublishWindow 将请求中的发布期写入 updates，并校验结束时间晚于开始时间，返回发布期是否有变化
+func applyPublishWindow(home *model.Home, req *model.HomeUpdateRequest, updates map[string]interface{}) (bool, error) {
+	publishAt, unpublishAt := home.PublishAt, home.UnpublishAt
+	if req.ClearSchedule {
+		publishAt, unpublishAt = nil, nil
+	}
+	if req.PublishAt != nil {
+		publishAt = req.PublishAt
+	}
+	if req.UnpublishAt != nil {
+		unpublishAt = req.UnpublishAt
+	}
+	if publishAt != nil && unpublishAt != nil && !unpublishAt.After(*publishAt) {
+		return false, ErrHomeInvalidWindow
+	}
+
+	changed := false
+	if !sameTime(publishAt, home.PublishAt) {
+		updates["publish_at"] = publishAt
+		changed = true
+	}
+	if !sameTime(unpublishAt, home.UnpublishAt) {
+		updates["unpublish_at"] = unpublishAt
+		changed = true
+	}
+	return changed, nil
+}
+
+// applySlot 将请求中的版位写入 updates，版位ID为 0 表示移出版位
+func (s *HomeService) applySlot(home *model.Home, slotID *int64, updates map[string]interface{}) error {
+	if slotID == nil {
+		return nil
+	}
+	if *slotID == 0 {
+		if home.SlotID != nil {
+			updates["slot_id"] = nil
+		}
+		return nil
+	}
+	if home.SlotID != nil && *home.SlotID == *slotID {
+		return nil
+	}
+	if _, err := s.slotRepo.FindByID(*slotID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrHomeSlotNotFound
+		}
+		return err
+	}
+	updates["slot_id"] = *slotID
+	return nil
+}
+
+// normalizeTags 去除标签名称首尾空白和重复项，nil 表示不修改标签
+func normalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	result := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// homeFilter 将请求中的版位编码和标签转换为查询条件，版位已禁用时 enabled 为 false
+func (s *HomeService) homeFilter(slotCode, tag string) (filter repository.HomeFilter, enabled bool, err error) {
+	filter.Tag = tag
+	if slotCode == "" {
+		return filter, true, nil
+	}
+
+	slot, err := s.slotRepo.FindByCode(slotCode)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return filter, false, ErrHomeSlotNotFound
+		}
+		return filter, false, err
+	}
+	filter.SlotID = slot.ID
+	filter.MaxItems = slot.MaxItems
+	filter.SortOrder = slot.SortOrder
+	return filter, slot.Status == 1, nil
 }
 
-// Delete 删除首页内容
-func (s *HomeService) Delete(req *model.HomeDeleteRequest) error {
+// publishHomeEvent 发布首页内容事件，入队失败只记录日志，不影响本次操作
+func publishHomeEvent(ctx context.Context, event string, data *webhook.HomeEvent) {
+	if err := webhook.Publish(context.WithoutCancel(ctx), event, data); err != nil {
+		logger.Warn("发布 webhook 事件失败",
+			zap.String("event", event),
+			zap.Int64("homeID", data.ID),
+			zap.Error(err))
+	}
+}
+
+// sameTime 比较两个可为空的时间是否相同
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// Delete 删除首页内容（移入回收站，保留期内可恢复）
+func (s *HomeService) Delete(ctx context.Context, req *model.HomeDeleteRequest, operatorID int64) error {
 	// 1. 检查记录是否存在
-	_, err := s.homeRepo.FindByID(req.ID)
+	home, err := s.homeRepo.FindByID(req.ID)
 	if err != nil {
 		return fmt.Errorf("首页内容不存在")
 	}
 
-	// 2. 执行删除
-	return s.homeRepo.Delete(req.ID)
+	// 2. 执行删除（软删除）
+	if err := s.homeRepo.Delete(req.ID); err != nil {
+		return err
+	}
+	invalidateHomeCache(ctx)
+	rescheduleHomePublishWindows()
+	s.searcher.Sync(req.ID)
+	audit.Record(ctx, operatorID, audit.ActionHomeDelete, audit.Target("home", req.ID), map[string]interface{}{
+		"title": home.Title,
+	})
+	publishHomeEvent(ctx, webhook.EventHomeDeleted, &webhook.HomeEvent{ID: req.ID, Title: home.Title, OperatorID: operatorID})
+	return nil
 }
 
-// Search 搜索首页内容
-func (s *HomeService) Search(req *model.HomeSearchRequest) (*model.HomeListDataResponse, error) {
+// Trash 获取回收站列表
+func (s *HomeService) Trash(req *model.HomeListRequest) (*model.HomeTrashListDataResponse, error) {
 	// 设置默认分页参数
 	if req.Page <= 0 {
 		req.Page = 1
@@ -125,24 +372,245 @@ func (s *HomeService) Search(req *model.HomeSearchRequest) (*model.HomeListDataR
 		req.PageSize = config.Config.Business.MaxPageSize
 	}
 
+	// 按版位、标签筛选（回收站不受版位状态和展示条数限制）
+	filter, _, err := s.homeFilter(req.Slot, req.Tag)
+	if err != nil {
+		return nil, err
+	}
+
 	// 查询数据
-	list, total, err := s.homeRepo.Search(req.Keyword, req.Page, req.PageSize)
+	homes, total, err := s.homeRepo.ListDeleted(repository.HomeFilter{SlotID: filter.SlotID, Tag: filter.Tag}, req.Page, req.PageSize)
 	if err != nil {
 		return nil, err
 	}
 
-	return &model.HomeListDataResponse{
+	// 计算预计彻底删除时间
+	retention := config.GetHomeTrashRetention()
+	list := make([]model.HomeTrashItem, 0, len(homes))
+	for _, home := range homes {
+		item := model.HomeTrashItem{Home: home, DeletedAt: home.DeletedAt.Time}
+		if retention > 0 {
+			purgeAt := home.DeletedAt.Time.Add(retention)
+			item.PurgeAt = &purgeAt
+		}
+		list = append(list, item)
+	}
+
+	return &model.HomeTrashListDataResponse{
 		List:  list,
 		Total: total,
 	}, nil
 }
 
-// GetByID 根据ID获取首页内容详情
-func (s *HomeService) GetByID(req *model.HomeGetByIDRequest) (*model.Home, error) {
-	// 调用仓储层查询
-	home, err := s.homeRepo.FindByID(req.ID)
+// Restore 从回收站恢复首页内容
+func (s *HomeService) Restore(ctx context.Context, req *model.HomeRestoreRequest, operatorID int64) error {
+	home, err := s.homeRepo.FindDeletedByID(req.ID)
+	if err != nil {
+		return ErrHomeNotInTrash
+	}
+
+	if err := s.homeRepo.Restore(req.ID); err != nil {
+		return err
+	}
+	invalidateHomeCache(ctx)
+	rescheduleHomePublishWindows()
+	s.searcher.Sync(req.ID)
+	audit.Record(ctx, operatorID, audit.ActionHomeRestore, audit.Target("home", req.ID), map[string]interface{}{
+		"title": home.Title,
+	})
+	publishHomeEvent(ctx, webhook.EventHomeRestored, &webhook.HomeEvent{ID: req.ID, Title: home.Title, OperatorID: operatorID})
+	return nil
+}
+
+// Purge 彻底删除回收站中的首页内容，不可恢复
+func (s *HomeService) Purge(ctx context.Context, req *model.HomePurgeRequest, operatorID int64) error {
+	home, err := s.homeRepo.FindDeletedByID(req.ID)
+	if err != nil {
+		return ErrHomeNotInTrash
+	}
+
+	if err := s.homeRepo.Purge(req.ID); err != nil {
+		return err
+	}
+	audit.Record(ctx, operatorID, audit.ActionHomePurge, audit.Target("home", req.ID), map[string]interface{}{
+		"title": home.Title,
+	})
+	publishHomeEvent(ctx, webhook.EventHomePurged, &webhook.HomeEvent{ID: req.ID, Title: home.Title, OperatorID: operatorID})
+	return nil
+}
+
+// Reorder 按给定顺序重写排序值（乐观锁）
+// 复用这些内容原有的排序值并按新顺序重新分配，不影响未包含在列表中的内容的相对位置。
+// 任一条的版本号与当前不一致时不做任何修改，返回 ErrHomeConflict 和冲突内容的最新排序、版本号
+func (s *HomeService) Reorder(ctx context.Context, req *model.HomeReorderRequest, operatorID int64) (*model.HomeReorderResult, error) {
+	// 1. 检查重复
+	ids := make([]int64, 0, len(req.Items))
+	seen := make(map[int64]bool, len(req.Items))
+	for _, item := range req.Items {
+		if seen[item.ID] {
+			return nil, ErrHomeDuplicateID
+		}
+		seen[item.ID] = true
+		ids = append(ids, item.ID)
+	}
+
+	// 2. 查询当前排序和版本号
+	homes, err := s.homeRepo.FindByIDs(ids)
 	if err != nil {
-		return nil, fmt.Errorf("首页内容不存在")
+		return nil, err
+	}
+	if len(homes) != len(ids) {
+		return nil, ErrHomeNotFound
+	}
+	current := make(map[int64]model.Home, len(homes))
+	for _, home := range homes {
+		current[home.ID] = home
+	}
+
+	// 3. 版本号已过期时直接返回冲突
+	if conflicts := staleHomeVersions(req.Items, current); len(conflicts) > 0 {
+		return &model.HomeReorderResult{Items: conflicts}, ErrHomeConflict
+	}
+
+	// 4. 原有排序值从小到大依次分配给新顺序，保证严格递增
+	sorts := make([]int, 0, len(homes))
+	for _, home := range homes {
+		sorts = append(sorts, home.Sort)
+	}
+	slices.Sort(sorts)
+	updates := make([]model.HomeSortVersion, len(req.Items))
+	for i, item := range req.Items {
+		sort := sorts[i]
+		if i > 0 && sort <= updates[i-1].Sort {
+			sort = updates[i-1].Sort + 1
+		}
+		updates[i] = model.HomeSortVersion{ID: item.ID, Sort: sort, Version: item.Version}
+	}
+
+	// 5. 同一事务中按版本号条件更新，期间被他人修改时整体回滚
+	conflictIDs, err := s.homeRepo.Reorder(updates)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflictIDs) > 0 {
+		latest, err := s.homeRepo.FindByIDs(conflictIDs)
+		if err != nil {
+			return nil, err
+		}
+		conflicts := make([]model.HomeSortVersion, 0, len(latest))
+		for _, home := range latest {
+			conflicts = append(conflicts, model.HomeSortVersion{ID: home.ID, Sort: home.Sort, Version: home.Version})
+		}
+		return &model.HomeReorderResult{Items: conflicts}, ErrHomeConflict
+	}
+
+	invalidateHomeCache(ctx)
+	s.searcher.Sync(ids...)
+	audit.Record(ctx, operatorID, audit.ActionHomeReorder, "", map[string]interface{}{
+		"ids": ids,
+	})
+
+	for i := range updates {
+		updates[i].Version++
+	}
+	return &model.HomeReorderResult{Items: updates}, nil
+}
+
+// staleHomeVersions 返回请求中版本号与当前不一致的内容的最新排序和版本号
+func staleHomeVersions(items []model.HomeReorderItem, current map[int64]model.Home) []model.HomeSortVersion {
+	var conflicts []model.HomeSortVersion
+	for _, item := range items {
+		if home := current[item.ID]; home.Version != item.Version {
+			conflicts = append(conflicts, model.HomeSortVersion{ID: home.ID, Sort: home.Sort, Version: home.Version})
+		}
+	}
+	return conflicts
+}
+
+// Search 搜索首页内容
+// 启用 ES 时使用全文检索（按相关度排序并返回高亮），ES 不可用或查询失败时回退到 MySQL 模糊搜索
+func (s *HomeService) Search(ctx context.Context, req *model.HomeSearchRequest) (*model.HomeSearchDataResponse, error) {
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = config.Config.Business.DefaultPageSize
+	}
+	if req.PageSize > config.Config.Business.MaxPageSize {
+		req.PageSize = config.Config.Business.MaxPageSize
+	}
+
+	// 按版位、标签筛选（搜索不受版位展示条数限制）
+	slotFilter, enabled, err := s.homeFilter(req.Slot, req.Tag)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return &model.HomeSearchDataResponse{List: []model.HomeSearchItem{}, Engine: SearchEngineMySQL}, nil
+	}
+	filter := repository.HomeFilter{SlotID: slotFilter.SlotID, Tag: slotFilter.Tag}
+	now := time.Now()
+
+	// 1. 全文检索（关键词为空时没有相关度可言，直接查 MySQL）
+	if req.Keyword != "" && s.searcher.Enabled() {
+		list, total, err := s.searcher.Search(ctx, req.Keyword, now, filter, req.Page, req.PageSize)
+		if err == nil {
+			return &model.HomeSearchDataResponse{
+				List:   list,
+				Total:  total,
+				Engine: SearchEngineElasticsearch,
+			}, nil
+		}
+		logger.Warn("ES 搜索失败，回退到 MySQL", zap.String("keyword", req.Keyword), zap.Error(err))
+	}
+
+	// 2. MySQL 模糊搜索
+	homes, total, err := s.homeRepo.Search(req.Keyword, now, filter, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]model.HomeSearchItem, 0, len(homes))
+	for _, home := range homes {
+		list = append(list, model.HomeSearchItem{Home: home})
+	}
+
+	return &model.HomeSearchDataResponse{
+		List:   list,
+		Total:  total,
+		Engine: SearchEngineMySQL,
+	}, nil
+}
+
+// homeIDs 提取首页内容ID
+func homeIDs(homes []model.Home) []int64 {
+	ids := make([]int64, 0, len(homes))
+	for _, home := range homes {
+		ids = append(ids, home.ID)
+	}
+	return ids
+}
+
+// GetByID 根据ID获取首页内容详情（优先读取缓存）
+// 与列表一致，只返回已启用且处于发布期内的内容；编辑人员通过预览接口查看未上线的内容
+func (s *HomeService) GetByID(ctx context.Context, req *model.HomeGetByIDRequest) (*model.Home, error) {
+	return loadHomeCached(ctx, s.cache, homeCacheDetail, strconv.FormatInt(req.ID, 10), func() (*model.Home, error) {
+		// 调用仓储层查询
+		home, err := s.homeRepo.FindPublishedByID(req.ID, time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("首页内容不存在")
+		}
+		return home, nil
+	})
+}
+
+// CacheStats 获取首页缓存命中统计
+func (s *HomeService) CacheStats() *model.HomeCacheStatsResponse {
+	ttl := config.GetHomeCacheTTL()
+	return &model.HomeCacheStatsResponse{
+		Enabled: ttl > 0 && s.cache != nil,
+		TTL:     int(ttl.Seconds()),
+		List:    homeCacheStats(homeCacheList),
+		Detail:  homeCacheStats(homeCacheDetail),
 	}
-	return home, nil
 }
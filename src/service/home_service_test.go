@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"hi-go/src/model"
+	"hi-go/src/service/webhook"
+	"hi-go/src/utils/redis"
+	"hi-go/src/utils/testutil"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHomeChangesPublishWebhookEvents(t *testing.T) {
+	testutil.SetupConfig(t, nil)
+	testutil.SetupRedis(t)
+	db := testutil.SetupDB(t, &model.Home{}, &model.HomeTag{}, &model.HomeRevision{}, &model.Webhook{}, &model.AuditLog{})
+
+	if err := db.Create(&model.Home{ID: 1, Title: "轮播", Status: 1}).Error; err != nil {
+		t.Fatal(err)
+	}
+	webhooks := []model.Webhook{
+		{ID: 1, Name: "更新", CallbackURL: "http://example.com/a", Event: webhook.EventHomeUpdated, Secret: "a", Enabled: 1, UserID: 1},
+		{ID: 2, Name: "删除", CallbackURL: "http://example.com/b", Event: webhook.EventHomeDeleted, Secret: "b", Enabled: 1, UserID: 1},
+	}
+	if err := db.Create(&webhooks).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	s := NewHomeService()
+	if err := s.Update(ctx, &model.HomeUpdateRequest{ID: 1, HomeFields: model.HomeFields{Title: "新标题"}}, 9); err != nil {
+		t.Fatalf("Update 失败: %v", err)
+	}
+	if err := s.Delete(ctx, &model.HomeDeleteRequest{ID: 1}, 9); err != nil {
+		t.Fatalf("Delete 失败: %v", err)
+	}
+
+	// 每个订阅者一个投递任务，读取任务中的事件名称和数据
+	values, err := redis.Client.HVals(ctx, "webhook:delivery:jobs").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []string
+	for _, v := range values {
+		var j struct {
+			WebhookID int64  `json:"webhook_id"`
+			Event     string `json:"event"`
+			Body      struct {
+				Data webhook.HomeEvent `json:"data"`
+			} `json:"body"`
+		}
+		if err := json.Unmarshal([]byte(v), &j); err != nil {
+			t.Fatal(err)
+		}
+		if j.Body.Data.ID != 1 || j.Body.Data.OperatorID != 9 {
+			t.Errorf("%s 事件数据 = %+v", j.Event, j.Body.Data)
+		}
+		switch j.Event {
+		case webhook.EventHomeUpdated:
+			// 更新事件携带更新前的标题和更新的字段
+			if j.WebhookID != 1 || j.Body.Data.Title != "轮播" || j.Body.Data.Changes["title"] != "新标题" {
+				t.Errorf("更新事件 = %+v", j)
+			}
+		case webhook.EventHomeDeleted:
+			if j.WebhookID != 2 || j.Body.Data.Title != "新标题" {
+				t.Errorf("删除事件 = %+v", j)
+			}
+		}
+		events = append(events, j.Event)
+	}
+	slices.Sort(events)
+	if !slices.Equal(events, []string{webhook.EventHomeDeleted, webhook.EventHomeUpdated}) {
+		t.Fatalf("事件 = %v", events)
+	}
+}
+
+func TestHomeGetByIDOnlyReturnsPublished(t *testing.T) {
+	testutil.SetupConfig(t, nil)
+	db := testutil.SetupDB(t, &model.Home{}, &model.HomeTag{})
+
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	homes := []model.Home{
+		{ID: 1, Title: "展示中", Status: 1, PublishAt: &past, UnpublishAt: &future},
+		{ID: 2, Title: "已禁用", Status: 0},
+		{ID: 3, Title: "未开始", Status: 1, PublishAt: &future},
+		{ID: 4, Title: "已结束", Status: 1, UnpublishAt: &past},
+		{ID: 5, Title: "不限时间", Status: 1},
+	}
+	if err := db.Create(&homes).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	s := NewHomeService()
+	for _, home := range homes {
+		got, err := s.GetByID(context.Background(), &model.HomeGetByIDRequest{ID: home.ID})
+		visible := home.ID == 1 || home.ID == 5
+		if visible && (err != nil || got.ID != home.ID) {
+			t.Errorf("%s: got=%v err=%v，期望返回", home.Title, got, err)
+		}
+		if !visible && err == nil {
+			t.Errorf("%s: 期望不返回，got=%+v", home.Title, got)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/service/audit"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrHomeSlotNotFound   = errors.New("版位不存在")
+	ErrHomeSlotCodeExists = errors.New("版位编码已存在")
+)
+
+// HomeSlotService 首页版位业务逻辑层
+type HomeSlotService struct {
+	slotRepo *repository.HomeSlotRepository
+	searcher *HomeSearcher
+}
+
+// NewHomeSlotService 创建首页版位服务实例
+func NewHomeSlotService() *HomeSlotService {
+	return &HomeSlotService{
+		slotRepo: repository.NewHomeSlotRepository(),
+		searcher: NewHomeSearcher(),
+	}
+}
+
+// List 获取全部版位及其下的内容数量
+func (s *HomeSlotService) List() (*model.HomeSlotListDataResponse, error) {
+	slots, err := s.slotRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.slotRepo.CountItems()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]model.HomeSlotItem, 0, len(slots))
+	for _, slot := range slots {
+		list = append(list, model.HomeSlotItem{HomeSlot: slot, ItemCount: counts[slot.ID]})
+	}
+	return &model.HomeSlotListDataResponse{List: list}, nil
+}
+
+// GetByID 获取版位详情
+func (s *HomeSlotService) GetByID(req *model.HomeSlotGetByIDRequest) (*model.HomeSlot, error) {
+	return s.findSlot(req.ID)
+}
+
+// Create 创建版位
+func (s *HomeSlotService) Create(ctx context.Context, req *model.HomeSlotCreateRequest, operatorID int64) (*model.HomeSlot, error) {
+	// 1. 检查编码是否已存在
+	exists, err := s.slotRepo.ExistsByCode(req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrHomeSlotCodeExists
+	}
+
+	// 2. 创建版位
+	slot := &model.HomeSlot{
+		Code:        req.Code,
+		Name:        req.Name,
+		Description: req.Description,
+		SortOrder:   req.SortOrder,
+		MaxItems:    req.MaxItems,
+		Status:      1,
+	}
+	if slot.SortOrder == "" {
+		slot.SortOrder = model.HomeSlotSortManual
+	}
+	if req.Status != nil {
+		slot.Status = *req.Status
+	}
+	if err := s.slotRepo.Create(slot); err != nil {
+		return nil, err
+	}
+
+	audit.Record(ctx, operatorID, audit.ActionHomeSlotCreate, audit.Target("home_slot", slot.ID), map[string]interface{}{
+		"code": slot.Code,
+	})
+	return slot, nil
+}
+
+// Update 修改版位
+func (s *HomeSlotService) Update(ctx context.Context, req *model.HomeSlotUpdateRequest, operatorID int64) error {
+	// 1. 检查版位是否存在
+	if _, err := s.findSlot(req.ID); err != nil {
+		return err
+	}
+
+	// 2. 构建更新数据
+	updates := make(map[string]interface{})
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+	if req.Description != "" {
+		updates["description"] = req.Description
+	}
+	if req.SortOrder != "" {
+		updates["sort_order"] = req.SortOrder
+	}
+	if req.MaxItems != nil {
+		updates["max_items"] = *req.MaxItems
+	}
+	if req.Status != nil {
+		updates["status"] = *req.Status
+	}
+	if len(updates) == 0 {
+		return errors.New("没有需要更新的字段")
+	}
+
+	// 3. 执行更新
+	if err := s.slotRepo.Update(req.ID, updates); err != nil {
+		return err
+	}
+	invalidateHomeCache(ctx)
+	audit.Record(ctx, operatorID, audit.ActionHomeSlotUpdate, audit.Target("home_slot", req.ID), updates)
+	return nil
+}
+
+// Delete 删除版位，版位下的内容保留并变为未分配版位
+func (s *HomeSlotService) Delete(ctx context.Context, req *model.HomeSlotDeleteRequest, operatorID int64) error {
+	slot, err := s.findSlot(req.ID)
+	if err != nil {
+		return err
+	}
+
+	ids, err := s.slotRepo.Delete(req.ID)
+	if err != nil {
+		return err
+	}
+	invalidateHomeCache(ctx)
+	s.searcher.Sync(ids...)
+	audit.Record(ctx, operatorID, audit.ActionHomeSlotDelete, audit.Target("home_slot", req.ID), map[string]interface{}{
+		"code":  slot.Code,
+		"items": len(ids),
+	})
+	return nil
+}
+
+// findSlot 根据ID查找版位，不存在时返回 ErrHomeSlotNotFound
+func (s *HomeSlotService) findSlot(id int64) (*model.HomeSlot, error) {
+	slot, err := s.slotRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrHomeSlotNotFound
+	}
+	return slot, err
+}
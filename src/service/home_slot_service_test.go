@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"hi-go/src/model"
+	"hi-go/src/utils/testutil"
+	"testing"
+)
+
+func TestHomeSlotCreateStatus(t *testing.T) {
+	testutil.SetupConfig(t, nil)
+	testutil.SetupRedis(t)
+	testutil.SetupDB(t, &model.HomeSlot{}, &model.AuditLog{})
+
+	disabled, enabled := 0, 1
+	tests := []struct {
+		code   string
+		status *int
+		want   int
+	}{
+		{"banner", &disabled, 0},
+		{"grid", &enabled, 1},
+		{"popup", nil, 1},
+	}
+
+	s := NewHomeSlotService()
+	for _, tt := range tests {
+		slot, err := s.Create(context.Background(), &model.HomeSlotCreateRequest{Code: tt.code, Name: tt.code, Status: tt.status}, 1)
+		if err != nil {
+			t.Fatalf("创建 %s 失败: %v", tt.code, err)
+		}
+		saved, err := s.GetByID(&model.HomeSlotGetByIDRequest{ID: slot.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved.Status != tt.want {
+			t.Errorf("%s 的状态为 %d，期望 %d", tt.code, saved.Status, tt.want)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/utils/logger"
+	"hi-go/src/utils/redis"
+	"strconv"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	homeStatsKeyPrefix    = "home:stats:"           // 每日计数 Hash，字段为 {内容ID}:{事件类型}
+	homeStatsDatesKey     = "home:stats:dates"      // 有待写入数据库计数的日期集合
+	homeStatsFlushLockKey = "home:stats_flush:lock" // 统计写入任务锁，多实例部署时同一周期只有一个实例执行
+	homeStatsMaxRangeDays = 366                     // 报表日期范围的最大天数
+)
+
+// ErrHomeStatsDateRange 报表日期范围无效
+var ErrHomeStatsDateRange = fmt.Errorf("日期范围无效：结束日期不能早于开始日期，且跨度不超过%d天", homeStatsMaxRangeDays)
+
+// HomeStatsService 首页内容曝光点击统计业务逻辑层
+// 上报只写 Redis，由定时任务按天汇总写入数据库
+type HomeStatsService struct {
+	statsRepo *repository.HomeStatsRepository
+}
+
+// NewHomeStatsService 创建统计服务实例
+func NewHomeStatsService() *HomeStatsService {
+	return &HomeStatsService{
+		statsRepo: repository.NewHomeStatsRepository(),
+	}
+}
+
+// Track 记录曝光/点击事件，累加到当天的 Redis 计数中
+// 不校验内容是否存在（上报路径不访问数据库），不存在的ID在写入数据库时丢弃
+func (s *HomeStatsService) Track(ctx context.Context, req *model.HomeTrackRequest) error {
+	// 同一批次中相同内容的相同事件合并后再累加
+	counts := make(map[string]int64, len(req.Events))
+	for _, event := range req.Events {
+		counts[homeStatsField(event.ID, event.Event)]++
+	}
+
+	date := time.Now().Format(time.DateOnly)
+	key := homeStatsKeyPrefix + date
+	_, err := redis.TxPipeline(ctx, func(pipe goredis.Pipeliner) error {
+		for field, n := range counts {
+			pipe.HIncrBy(ctx, key, field, n)
+		}
+		pipe.SAdd(ctx, homeStatsDatesKey, date)
+		return nil
+	})
+	return err
+}
+
+// Report 按内容汇总日期范围内的曝光点击数据并计算点击率
+// 最近一个写入周期内的上报尚未写入数据库，不包含在报表中
+func (s *HomeStatsService) Report(req *model.HomeStatsReportRequest) (*model.HomeStatsReportResponse, error) {
+	// 1. 校验日期范围
+	start, err := time.ParseInLocation(time.DateOnly, req.StartDate, time.Local)
+	if err != nil {
+		return nil, ErrHomeStatsDateRange
+	}
+	end, err := time.ParseInLocation(time.DateOnly, req.EndDate, time.Local)
+	if err != nil {
+		return nil, ErrHomeStatsDateRange
+	}
+	if end.Before(start) || end.Sub(start) >= homeStatsMaxRangeDays*24*time.Hour {
+		return nil, ErrHomeStatsDateRange
+	}
+
+	// 2. 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = config.Config.Business.DefaultPageSize
+	}
+	if req.PageSize > config.Config.Business.MaxPageSize {
+		req.PageSize = config.Config.Business.MaxPageSize
+	}
+
+	// 3. 查询并计算点击率
+	list, total, err := s.statsRepo.Report(start, end, req.ID, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	if list == nil {
+		list = []model.HomeStatsItem{}
+	}
+	for i := range list {
+		if list[i].Impressions > 0 {
+			list[i].CTR = float64(list[i].Clicks) / float64(list[i].Impressions)
+		}
+	}
+	return &model.HomeStatsReportResponse{
+		List:  list,
+		Total: total,
+	}, nil
+}
+
+// StartHomeStatsFlusher 启动统计写入任务，定期将 Redis 中的计数累加到数据库
+// 未配置执行间隔时不启动（计数会一直保留在 Redis 中）；ctx 取消后任务退出
+func StartHomeStatsFlusher(ctx context.Context) {
+	interval := config.GetHomeStatsFlushInterval()
+	if interval <= 0 {
+		logger.Info("首页统计写入任务未开启")
+		return
+	}
+
+	statsRepo := repository.NewHomeStatsRepository()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			flushHomeStats(ctx, statsRepo, interval)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("首页统计写入任务已启动", zap.Duration("interval", interval))
+}
+
+// flushHomeStats 执行一次统计写入
+func flushHomeStats(ctx context.Context, statsRepo *repository.HomeStatsRepository, interval time.Duration) {
+	// 锁在本周期内不释放，其他实例本周期内跳过
+	acquired, err := redis.SetNX(ctx, homeStatsFlushLockKey, 1, interval)
+	if err != nil {
+		logger.Warn("获取统计写入任务锁失败", zap.Error(err))
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	dates, err := redis.SMembers(ctx, homeStatsDatesKey)
+	if err != nil {
+		logger.Warn("读取待写入统计日期失败", zap.Error(err))
+		return
+	}
+	for _, date := range dates {
+		if err := flushHomeStatsDate(ctx, statsRepo, date); err != nil {
+			logger.Error("首页统计写入失败", zap.String("date", date), zap.Error(err))
+		}
+	}
+}
+
+// flushHomeStatsDate 将某一天的计数写入数据库
+// 计数 Hash 先改名再读取，改名后的上报写入新的 Hash，不会丢失也不会重复累加；
+// 写入数据库失败时保留改名后的 Hash 并重新加入日期集合，下个周期优先重试
+func flushHomeStatsDate(ctx context.Context, statsRepo *repository.HomeStatsRepository, date string) error {
+	statDate, err := time.ParseInLocation(time.DateOnly, date, time.Local)
+	if err != nil {
+		// 无法识别的日期直接丢弃
+		_, err := redis.SRem(ctx, homeStatsDatesKey, date)
+		return err
+	}
+
+	// 先从集合移除再改名：改名后的新上报会重新加入集合，留到下个周期写入
+	if _, err := redis.SRem(ctx, homeStatsDatesKey, date); err != nil {
+		return err
+	}
+
+	// 1. 上个周期写入失败遗留的数据
+	key := homeStatsKeyPrefix + date
+	flushingKey := key + ":flushing"
+	if err := flushHomeStatsKey(ctx, statsRepo, statDate, flushingKey); err != nil {
+		redis.SAdd(ctx, homeStatsDatesKey, date)
+		return err
+	}
+
+	// 2. 本周期的新数据
+	n, err := redis.Exists(ctx, key)
+	if err != nil || n == 0 {
+		return err
+	}
+	if err := redis.Rename(ctx, key, flushingKey); err != nil {
+		return err
+	}
+	if err := flushHomeStatsKey(ctx, statsRepo, statDate, flushingKey); err != nil {
+		redis.SAdd(ctx, homeStatsDatesKey, date)
+		return err
+	}
+	return nil
+}
+
+// flushHomeStatsKey 将计数 Hash 累加到数据库，成功后删除该 Hash
+// 上报时不查数据库，不存在的内容ID在这里丢弃，不会写入统计表
+func flushHomeStatsKey(ctx context.Context, statsRepo *repository.HomeStatsRepository, date time.Time, key string) error {
+	counts, err := redis.HGetAll(ctx, key)
+	if err != nil || len(counts) == 0 {
+		return err
+	}
+	stats, err := knownHomeStats(statsRepo, parseHomeStats(date, counts))
+	if err != nil {
+		return err
+	}
+	if err := statsRepo.Accumulate(stats); err != nil {
+		return err
+	}
+	_, err = redis.Del(ctx, key)
+	return err
+}
+
+// knownHomeStats 过滤掉不存在的内容的统计记录
+func knownHomeStats(statsRepo *repository.HomeStatsRepository, stats []model.HomeDailyStat) ([]model.HomeDailyStat, error) {
+	ids := make([]int64, 0, len(stats))
+	for _, stat := range stats {
+		ids = append(ids, stat.HomeID)
+	}
+	existing, err := statsRepo.ExistingHomeIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int64]bool, len(existing))
+	for _, id := range existing {
+		known[id] = true
+	}
+
+	kept := stats[:0]
+	for _, stat := range stats {
+		if known[stat.HomeID] {
+			kept = append(kept, stat)
+		}
+	}
+	if dropped := len(stats) - len(kept); dropped > 0 {
+		logger.Warn("丢弃不存在的首页内容的统计数据", zap.Int("count", dropped))
+	}
+	return kept, nil
+}
+
+// homeStatsField 生成计数 Hash 的字段名
+func homeStatsField(homeID int64, event string) string {
+	return strconv.FormatInt(homeID, 10) + ":" + event
+}
+
+// parseHomeStats 将计数 Hash 转换为每日统计记录，无法识别的字段忽略
+func parseHomeStats(date time.Time, counts map[string]string) []model.HomeDailyStat {
+	byHome := make(map[int64]*model.HomeDailyStat)
+	for field, value := range counts {
+		idStr, event, _ := strings.Cut(field, ":")
+		homeID, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			continue
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+
+		stat, ok := byHome[homeID]
+		if !ok {
+			stat = &model.HomeDailyStat{HomeID: homeID, StatDate: date}
+			byHome[homeID] = stat
+		}
+		switch event {
+		case model.HomeEventImpression:
+			stat.Impressions += n
+		case model.HomeEventClick:
+			stat.Clicks += n
+		}
+	}
+
+	stats := make([]model.HomeDailyStat, 0, len(byHome))
+	for _, stat := range byHome {
+		stats = append(stats, *stat)
+	}
+	return stats
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"hi-go/src/config"
+	"hi-go/src/repository"
+	"hi-go/src/service/audit"
+	"hi-go/src/utils/logger"
+	"hi-go/src/utils/redis"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	homeTrashPurgeLockKey = "home:trash_purge:lock" // 回收站清理任务锁，多实例部署时同一周期只有一个实例执行
+	homeTrashPurgeBatch   = 500                     // 单次删除的最大条数，避免长时间锁表
+)
+
+// StartHomeTrashPurger 启动回收站清理任务，定期彻底删除超过保留期的首页内容
+// 未配置保留天数或执行间隔时不启动；ctx 取消后任务退出
+func StartHomeTrashPurger(ctx context.Context) {
+	retention := config.GetHomeTrashRetention()
+	interval := config.GetHomeTrashPurgeInterval()
+	if retention <= 0 || interval <= 0 {
+		logger.Info("回收站自动清理未开启")
+		return
+	}
+
+	homeRepo := repository.NewHomeRepository()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			purgeHomeTrash(ctx, homeRepo, retention, interval)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	logger.Info("回收站自动清理已启动",
+		zap.Duration("retention", retention),
+		zap.Duration("interval", interval))
+}
+
+// purgeHomeTrash 执行一次回收站清理
+func purgeHomeTrash(ctx context.Context, homeRepo *repository.HomeRepository, retention, interval time.Duration) {
+	// 锁在本周期内不释放，其他实例本周期内跳过
+	acquired, err := redis.SetNX(ctx, homeTrashPurgeLockKey, 1, interval)
+	if err != nil {
+		logger.Warn("获取回收站清理任务锁失败", zap.Error(err))
+		return
+	}
+	if !acquired {
+		return
+	}
+
+	before := time.Now().Add(-retention)
+	var total int64
+	for {
+		n, err := homeRepo.PurgeDeletedBefore(before, homeTrashPurgeBatch)
+		total += n
+		if err != nil {
+			logger.Error("回收站清理失败", zap.Int64("purged", total), zap.Error(err))
+			break
+		}
+		if n < homeTrashPurgeBatch {
+			break
+		}
+	}
+
+	if total > 0 {
+		audit.Record(ctx, 0, audit.ActionHomePurge, "", map[string]interface{}{
+			"count":          total,
+			"deleted_before": before.Format(time.DateTime),
+		})
+		logger.Info("回收站清理完成", zap.Int64("purged", total))
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/utils/logger"
+	"hi-go/src/utils/redis"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LoginLockedError 登录失败次数过多，账号或IP被临时锁定
+type LoginLockedError struct {
+	RetryAfter time.Duration // 距离解锁的剩余时间
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("登录失败次数过多，请 %d 秒后重试", int64(e.RetryAfter.Seconds()))
+}
+
+// loginLimiter 登录防暴力破解
+// 按用户名和客户端IP分别统计失败次数，超过阈值后在锁定时长内拒绝登录
+type loginLimiter struct{}
+
+// loginCounter 一个维度（用户名或IP）的失败计数配置
+type loginCounter struct {
+	failKey     string
+	lockKey     string
+	maxFailures int
+}
+
+// counters 返回需要统计的维度，阈值为 0 的维度不统计
+func (l *loginLimiter) counters(username, ip string) []loginCounter {
+	biz := config.Config.Business
+	counters := make([]loginCounter, 0, 2)
+	if biz.LoginMaxFailures > 0 && username != "" {
+		counters = append(counters, loginCounter{
+			failKey:     fmt.Sprintf("login:fail:user:%s", username),
+			lockKey:     fmt.Sprintf("login:lock:user:%s", username),
+			maxFailures: biz.LoginMaxFailures,
+		})
+	}
+	if biz.LoginIPMaxFailures > 0 && ip != "" {
+		counters = append(counters, loginCounter{
+			failKey:     fmt.Sprintf("login:fail:ip:%s", ip),
+			lockKey:     fmt.Sprintf("login:lock:ip:%s", ip),
+			maxFailures: biz.LoginIPMaxFailures,
+		})
+	}
+	return counters
+}
+
+// check 检查用户名或IP是否处于锁定状态
+func (l *loginLimiter) check(username, ip string) error {
+	ctx := context.Background()
+	for _, counter := range l.counters(username, ip) {
+		ttl, err := redis.TTL(ctx, counter.lockKey)
+		if err != nil {
+			// Redis 异常时不阻断登录
+			logger.Warn("查询登录锁定状态失败", zap.String("key", counter.lockKey), zap.Error(err))
+			continue
+		}
+		if ttl > 0 {
+			return &LoginLockedError{RetryAfter: ttl}
+		}
+	}
+	return nil
+}
+
+// recordFailure 记录一次登录失败，达到阈值时锁定并返回 LoginLockedError
+func (l *loginLimiter) recordFailure(username, ip string) error {
+	ctx := context.Background()
+	var locked error
+	for _, counter := range l.counters(username, ip) {
+		count, err := redis.Incr(ctx, counter.failKey)
+		if err != nil {
+			logger.Warn("记录登录失败次数失败", zap.String("key", counter.failKey), zap.Error(err))
+			continue
+		}
+		// 第一次失败时开始计时
+		if count == 1 {
+			if _, err := redis.Expire(ctx, counter.failKey, config.GetLoginFailureWindow()); err != nil {
+				logger.Warn("设置登录失败计数过期时间失败", zap.String("key", counter.failKey), zap.Error(err))
+			}
+		}
+		if count < int64(counter.maxFailures) {
+			continue
+		}
+
+		// 达到阈值，锁定并重新计数
+		lockout := config.GetLoginLockoutDuration()
+		if err := redis.Set(ctx, counter.lockKey, count, lockout); err != nil {
+			logger.Warn("设置登录锁定失败", zap.String("key", counter.lockKey), zap.Error(err))
+			continue
+		}
+		redis.Del(ctx, counter.failKey)
+		logger.Warn("登录失败次数过多，已锁定",
+			zap.String("key", counter.lockKey),
+			zap.Int64("failures", count),
+			zap.Duration("lockout", lockout))
+		locked = &LoginLockedError{RetryAfter: lockout}
+	}
+	return locked
+}
+
+// reset 登录成功后清除用户名的失败计数（IP 计数保留，防止撞库）
+func (l *loginLimiter) reset(username string) {
+	if _, err := redis.Del(context.Background(), fmt.Sprintf("login:fail:user:%s", username)); err != nil {
+		logger.Warn("清除登录失败计数失败", zap.String("username", username), zap.Error(err))
+	}
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/utils/oidc"
+	"hi-go/src/utils/redis"
+	"hi-go/src/utils/snowflake"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOAuthProviderNotFound = errors.New("不支持的登录方式")
+	ErrOAuthStateInvalid     = errors.New("登录请求已失效，请重新发起登录")
+	ErrOAuthDenied           = errors.New("第三方登录已取消或被拒绝")
+	ErrOAuthAccountNotLinked = errors.New("该第三方账号未绑定本站用户")
+)
+
+// OAuthStateCookie 发起登录时写入浏览器的 Cookie 名称，值为 state 的哈希
+// 回调时要求 Cookie 与 state 对应，防止攻击者把自己发起的授权回调诱导受害者打开（登录 CSRF）
+const OAuthStateCookie = "oauth_state"
+
+// 自动创建用户时，用户名中允许保留的字符
+var usernameInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// oauthState 发起登录时保存的授权请求信息
+type oauthState struct {
+	Provider     string `json:"provider"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	Device       string `json:"device"`
+}
+
+// 第三方登录业务逻辑层（OAuth2 授权码 + PKCE，OIDC）
+type OAuthService struct {
+	identityRepo *repository.UserIdentityRepository
+	userRepo     *repository.UserRepository
+	tokenRepo    *repository.TokenRepository
+	authService  *AuthService
+
+	mu        sync.Mutex
+	providers map[string]*oidc.Provider // 已创建的身份提供方客户端，缓存发现文档和公钥
+}
+
+// 创建第三方登录服务实例
+func NewOAuthService() *OAuthService {
+	return &OAuthService{
+		identityRepo: repository.NewUserIdentityRepository(),
+		userRepo:     repository.NewUserRepository(),
+		tokenRepo:    repository.NewTokenRepository(),
+		authService:  NewAuthService(),
+		providers:    make(map[string]*oidc.Provider),
+	}
+}
+
+// Start 发起第三方登录，返回身份提供方的授权地址和需要写入 OAuthStateCookie 的值
+func (s *OAuthService) Start(provider, device string) (authURL, binding string, err error) {
+	p, err := s.provider(provider)
+	if err != nil {
+		return "", "", err
+	}
+
+	// 1. 生成 nonce 和 PKCE 校验码
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return "", "", err
+	}
+
+	// 2. 保存授权请求，state 即一次性令牌，回调时校验并作废
+	data, err := json.Marshal(&oauthState{
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		Device:       device,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	ctx := context.Background()
+	state, err := s.tokenRepo.Create(ctx, repository.TokenPurposeOAuthState, string(data), config.GetOAuthStateTTL())
+	if err != nil {
+		return "", "", err
+	}
+
+	// 3. 生成授权地址
+	authURL, err = p.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, stateBinding(state), nil
+}
+
+// Callback 处理身份提供方回调：换取并验证 ID Token，绑定或创建用户后完成登录
+// binding 为浏览器携带的 OAuthStateCookie，必须与 state 对应
+func (s *OAuthService) Callback(ctx context.Context, provider, binding string, req *model.OAuthCallbackRequest, client *model.ClientInfo) (*model.LoginDataResponse, error) {
+	if req.Error != "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrOAuthDenied, req.Error, req.ErrorDescription)
+	}
+	if req.Code == "" || req.State == "" {
+		return nil, ErrOAuthStateInvalid
+	}
+
+	p, err := s.provider(provider)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. 确认回调与发起登录的是同一浏览器，再校验并作废 state
+	if subtle.ConstantTimeCompare([]byte(binding), []byte(stateBinding(req.State))) != 1 {
+		return nil, ErrOAuthStateInvalid
+	}
+	value, err := s.tokenRepo.Consume(ctx, repository.TokenPurposeOAuthState, req.State)
+	if errors.Is(err, redis.ErrKeyNotFound) {
+		return nil, ErrOAuthStateInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state oauthState
+	if err := json.Unmarshal([]byte(value), &state); err != nil || state.Provider != provider {
+		return nil, ErrOAuthStateInvalid
+	}
+
+	// 2. 使用授权码换取令牌，并验证 ID Token
+	token, err := p.Exchange(ctx, req.Code, state.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := p.VerifyIDToken(ctx, token.IDToken, state.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 查找已绑定的用户，或按邮箱绑定、自动创建
+	user, err := s.resolveUser(provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != 1 {
+		auditLoginFailed(ctx, user.ID, user.Username, "disabled")
+		return nil, ErrUserDisabled
+	}
+
+	// 4. 与密码登录一致：两步验证或签发令牌
+	return s.authService.completeLogin(ctx, user, state.Device, client, "oauth:"+provider)
+}
+
+// resolveUser 根据第三方身份找到本站用户
+func (s *OAuthService) resolveUser(provider string, claims *oidc.Claims) (*model.User, error) {
+	// 1. 已绑定
+	identity, err := s.identityRepo.FindByProviderSubject(provider, claims.Subject)
+	if err == nil {
+		user, err := s.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			return nil, ErrUserNotFound
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	identity = &model.UserIdentity{
+		ID:       snowflake.MustGenerate(),
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+
+	// 2. 双方都已验证的邮箱视为同一人，自动绑定到已有用户
+	if claims.Email != "" && claims.EmailVerified {
+		user, err := s.userRepo.FindByEmail(claims.Email)
+		if err == nil && user.EmailVerifiedAt != nil {
+			identity.UserID = user.ID
+			if err := s.identityRepo.Create(identity); err != nil {
+				return nil, err
+			}
+			return user, nil
+		}
+	}
+
+	// 3. 自动创建用户
+	if !config.Config.OAuth.Providers[provider].AutoCreate {
+		return nil, ErrOAuthAccountNotLinked
+	}
+	user, err := s.newUser(provider, claims)
+	if err != nil {
+		return nil, err
+	}
+	identity.UserID = user.ID
+	if err := s.identityRepo.CreateWithUser(user, identity); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// newUser 根据第三方身份信息构建新用户
+// 密码设置为随机值，用户可通过找回密码设置本站密码
+func (s *OAuthService) newUser(provider string, claims *oidc.Claims) (*model.User, error) {
+	username, err := s.availableUsername(provider, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	randomPassword, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(randomPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		ID:       snowflake.MustGenerate(),
+		Username: username,
+		Password: string(hashedPassword),
+		Nickname: truncate(claims.Name, 50),
+		Avatar:   truncate(claims.Picture, 255),
+		Status:   1,
+	}
+
+	// 只保存已验证且未被占用的邮箱
+	if claims.Email != "" && claims.EmailVerified && len(claims.Email) <= 100 {
+		exists, err := s.userRepo.ExistsByEmail(claims.Email)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			now := time.Now()
+			user.Email = claims.Email
+			user.EmailVerifiedAt = &now
+		}
+	}
+	return user, nil
+}
+
+// availableUsername 生成未被占用的用户名，优先使用身份提供方的用户名或邮箱前缀
+func (s *OAuthService) availableUsername(provider string, claims *oidc.Claims) (string, error) {
+	base := claims.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	base = truncate(usernameInvalidChars.ReplaceAllString(base, ""), 40)
+	if len(base) < max(3, config.Config.Business.UsernameMinLength) {
+		base = truncate(provider, 30) + "_user"
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		exists, err := s.userRepo.ExistsByUsername(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		suffix, err := oidc.RandomString()
+		if err != nil {
+			return "", err
+		}
+		candidate = base + "_" + strings.ToLower(suffix[:6])
+	}
+	return "", ErrUserExists
+}
+
+// provider 获取身份提供方客户端
+func (s *OAuthService) provider(name string) (*oidc.Provider, error) {
+	cfg, ok := config.Config.OAuth.Providers[name]
+	if !ok || cfg.Issuer == "" || cfg.ClientID == "" {
+		return nil, ErrOAuthProviderNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.providers[name]; ok {
+		return p, nil
+	}
+
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+	p := oidc.NewProvider(&oidc.Config{
+		Issuer:       cfg.Issuer,
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		RedirectURL:  fmt.Sprintf("%s/api/user/oauth/%s/callback", strings.TrimRight(config.Config.OAuth.CallbackBaseURL, "/"), name),
+		Scopes:       scopes,
+	})
+	s.providers[name] = p
+	return p, nil
+}
+
+// stateBinding 计算 state 的哈希，Cookie 中不直接保存 state
+func stateBinding(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+// truncate 按字符数截断字符串
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
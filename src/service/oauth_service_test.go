@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/utils/oidc"
+	"hi-go/src/utils/testutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestOAuthCallbackRequiresStateBinding(t *testing.T) {
+	// 身份提供方只提供发现文档，令牌端点拒绝所有请求：校验通过后会在换取令牌时失败
+	mux := http.NewServeMux()
+	issuer := httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidc.Discovery{
+			Issuer:                issuer.URL,
+			AuthorizationEndpoint: issuer.URL + "/authorize",
+			TokenEndpoint:         issuer.URL + "/token",
+			JWKSURI:               issuer.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+	})
+
+	testutil.SetupConfig(t, func(cfg *config.AppConfig) {
+		cfg.OAuth = config.OAuthConfig{
+			CallbackBaseURL: "https://app.example.com",
+			StateTTL:        600,
+			Providers: map[string]config.OAuthProviderConfig{
+				"test": {Issuer: issuer.URL, ClientID: "client"},
+			},
+		}
+	})
+	testutil.SetupRedis(t)
+
+	s := NewOAuthService()
+	authURL, binding, err := s.Start("test", "")
+	if err != nil {
+		t.Fatalf("Start 失败: %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := u.Query().Get("state")
+	if state == "" || binding == "" || binding == state {
+		t.Fatalf("state=%q binding=%q", state, binding)
+	}
+
+	ctx := context.Background()
+	req := &model.OAuthCallbackRequest{Code: "code", State: state}
+	callback := func(binding string) error {
+		_, err := s.Callback(ctx, "test", binding, req, &model.ClientInfo{})
+		return err
+	}
+
+	// 1. 缺少 Cookie 或 Cookie 属于其他登录请求时拒绝，且不作废 state
+	if err := callback(""); !errors.Is(err, ErrOAuthStateInvalid) {
+		t.Fatalf("缺少 Cookie: err = %v", err)
+	}
+	_, other, err := s.Start("test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := callback(other); !errors.Is(err, ErrOAuthStateInvalid) {
+		t.Fatalf("Cookie 不匹配: err = %v", err)
+	}
+
+	// 2. Cookie 匹配时继续换取令牌
+	if err := callback(binding); !errors.Is(err, oidc.ErrExchangeFailed) {
+		t.Fatalf("Cookie 匹配: err = %v，期望 ErrExchangeFailed", err)
+	}
+
+	// 3. state 已作废，不能重复使用
+	if err := callback(binding); !errors.Is(err, ErrOAuthStateInvalid) {
+		t.Fatalf("重复回调: err = %v", err)
+	}
+}
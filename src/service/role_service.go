@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/service/audit"
+	"hi-go/src/utils/logger"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrRoleNotFound     = errors.New("角色不存在")
+	ErrRoleNotRevocable = errors.New("默认角色不能撤销")
+)
+
+// 内置权限定义
+var defaultPermissions = []model.Permission{
+	{Code: model.PermissionHomeWrite, Name: "首页内容编辑", Description: "创建、修改、删除首页内容"},
+	{Code: model.PermissionUserManage, Name: "用户管理", Description: "管理用户账号和角色"},
+}
+
+// 内置角色定义及其权限
+var defaultRoles = []struct {
+	Role        model.Role
+	Permissions []string
+}{
+	{
+		Role: model.Role{Code: model.RoleUser, Name: "普通用户", Description: "所有用户默认拥有"},
+	},
+	{
+		Role:        model.Role{Code: model.RoleContentEditor, Name: "内容编辑", Description: "维护首页内容"},
+		Permissions: []string{model.PermissionHomeWrite},
+	},
+	{
+		Role:        model.Role{Code: model.RoleAdmin, Name: "管理员", Description: "拥有全部权限"},
+		Permissions: []string{model.PermissionHomeWrite, model.PermissionUserManage},
+	},
+}
+
+// 角色权限业务逻辑层
+type RoleService struct {
+	roleRepo    *repository.RoleRepository
+	userRepo    *repository.UserRepository
+	sessionRepo *repository.SessionRepository
+}
+
+// 创建角色服务实例
+func NewRoleService() *RoleService {
+	return &RoleService{
+		roleRepo:    repository.NewRoleRepository(),
+		userRepo:    repository.NewUserRepository(),
+		sessionRepo: repository.NewSessionRepository(),
+	}
+}
+
+// SeedDefaults 初始化内置角色和权限，并为配置中的用户授予管理员角色
+// 可重复执行，已存在的数据不会被覆盖
+func (s *RoleService) SeedDefaults() error {
+	// 1. 创建内置权限
+	permissions := make(map[string]model.Permission, len(defaultPermissions))
+	for _, p := range defaultPermissions {
+		permission := p
+		if err := s.roleRepo.EnsurePermission(&permission); err != nil {
+			return fmt.Errorf("初始化权限 %s 失败: %w", p.Code, err)
+		}
+		permissions[permission.Code] = permission
+	}
+
+	// 2. 创建内置角色并关联权限
+	for _, def := range defaultRoles {
+		role := def.Role
+		rolePermissions := make([]model.Permission, 0, len(def.Permissions))
+		for _, code := range def.Permissions {
+			rolePermissions = append(rolePermissions, permissions[code])
+		}
+		if err := s.roleRepo.EnsureRole(&role, rolePermissions); err != nil {
+			return fmt.Errorf("初始化角色 %s 失败: %w", role.Code, err)
+		}
+	}
+
+	// 3. 为配置中的用户授予管理员角色
+	admin, err := s.roleRepo.FindByCode(model.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	for _, username := range config.Config.RBAC.AdminUsernames {
+		user, err := s.userRepo.FindByUsername(username)
+		if err != nil {
+			logger.Warn("管理员用户不存在，跳过授权", zap.String("username", username))
+			continue
+		}
+		if err := s.roleRepo.AssignRole(user.ID, admin.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetUserRoles 获取用户的角色编码列表（始终包含默认的 user 角色）
+func (s *RoleService) GetUserRoles(userID int64) ([]string, error) {
+	codes, err := s.roleRepo.FindCodesByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	roles := []string{model.RoleUser}
+	for _, code := range codes {
+		if code != model.RoleUser {
+			roles = append(roles, code)
+		}
+	}
+	return roles, nil
+}
+
+// HasPermissions 判断角色集合是否拥有全部指定权限
+func (s *RoleService) HasPermissions(roles []string, permissions ...string) (bool, error) {
+	owned, err := s.roleRepo.FindPermissionCodes(roles)
+	if err != nil {
+		return false, err
+	}
+
+	ownedSet := make(map[string]struct{}, len(owned))
+	for _, code := range owned {
+		ownedSet[code] = struct{}{}
+	}
+	for _, permission := range permissions {
+		if _, ok := ownedSet[permission]; !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ListRoles 获取全部角色及其权限
+func (s *RoleService) ListRoles() ([]model.Role, error) {
+	return s.roleRepo.FindAll()
+}
+
+// GrantRole 为用户授予角色
+// 令牌中的角色在签发时确定，与撤销角色一致，授予后吊销该用户的全部会话，新角色在重新登录后生效
+func (s *RoleService) GrantRole(ctx context.Context, operatorID, userID int64, roleCode string) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return ErrUserNotFound
+	}
+	role, err := s.roleRepo.FindByCode(roleCode)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+	if err := s.roleRepo.AssignRole(userID, role.ID); err != nil {
+		return err
+	}
+	if err := s.sessionRepo.DeleteAll(ctx, fmt.Sprintf("%d", userID)); err != nil {
+		return err
+	}
+	audit.Record(ctx, operatorID, audit.ActionUserRoleGrant, audit.Target("user", userID), map[string]interface{}{
+		"role": roleCode,
+	})
+	return nil
+}
+
+// RevokeRole 撤销用户的角色
+// 令牌中的角色在签发时确定，撤销后同时吊销该用户的全部会话，使权限立即收回
+func (s *RoleService) RevokeRole(ctx context.Context, operatorID, userID int64, roleCode string) error {
+	if roleCode == model.RoleUser {
+		return ErrRoleNotRevocable
+	}
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return ErrUserNotFound
+	}
+	role, err := s.roleRepo.FindByCode(roleCode)
+	if err != nil {
+		return ErrRoleNotFound
+	}
+	if err := s.roleRepo.RemoveRole(userID, role.ID); err != nil {
+		return err
+	}
+	if err := s.sessionRepo.DeleteAll(ctx, fmt.Sprintf("%d", userID)); err != nil {
+		return err
+	}
+	audit.Record(ctx, operatorID, audit.ActionUserRoleRevoke, audit.Target("user", userID), map[string]interface{}{
+		"role": roleCode,
+	})
+	return nil
+}
+
+// SetRoles 覆盖设置用户的角色（user 角色始终保留，无需传入）
+// 与撤销角色一致，设置后吊销该用户的全部会话，新角色在重新登录后生效
+func (s *RoleService) SetRoles(userID int64, roleCodes []string) error {
+	if _, err := s.userRepo.FindByID(userID); err != nil {
+		return ErrUserNotFound
+	}
+
+	// 1. 校验角色编码（去重，忽略默认角色）
+	codes := make([]string, 0, len(roleCodes))
+	seen := make(map[string]struct{}, len(roleCodes))
+	for _, code := range roleCodes {
+		if _, ok := seen[code]; ok || code == model.RoleUser {
+			continue
+		}
+		seen[code] = struct{}{}
+		codes = append(codes, code)
+	}
+	roles, err := s.roleRepo.FindByCodes(codes)
+	if err != nil {
+		return err
+	}
+	if len(roles) != len(codes) {
+		return ErrRoleNotFound
+	}
+
+	// 2. 覆盖用户角色
+	roleIDs := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	if err := s.roleRepo.ReplaceRoles(userID, roleIDs); err != nil {
+		return err
+	}
+
+	return s.sessionRepo.DeleteAll(context.Background(), fmt.Sprintf("%d", userID))
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/service/audit"
+	"hi-go/src/utils/crypto"
+	"hi-go/src/utils/totp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTwoFactorEnabled     = errors.New("两步验证已开启")
+	ErrTwoFactorNotEnabled  = errors.New("两步验证未开启")
+	ErrTwoFactorNotEnrolled = errors.New("请先获取两步验证密钥")
+	ErrTwoFactorInvalidCode = errors.New("验证码错误")
+)
+
+// 恢复码数量
+const recoveryCodeCount = 10
+
+// 两步验证（TOTP）业务逻辑层
+type TwoFactorService struct {
+	twoFactorRepo *repository.TwoFactorRepository
+	userRepo      *repository.UserRepository
+}
+
+// 创建两步验证服务实例
+func NewTwoFactorService() *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo: repository.NewTwoFactorRepository(),
+		userRepo:      repository.NewUserRepository(),
+	}
+}
+
+// IsEnabled 用户是否已开启两步验证
+// 只有记录不存在视为未开启，查询失败时返回错误，避免数据库异常时跳过两步验证
+func (s *TwoFactorService) IsEnabled(userID int64) (bool, error) {
+	tf, err := s.twoFactorRepo.FindByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return tf.Enabled == 1, nil
+}
+
+// Enroll 生成新的 TOTP 密钥（待确认状态），返回供认证器绑定的信息
+// 重复调用会覆盖尚未确认的密钥
+func (s *TwoFactorService) Enroll(userID int64) (*model.TwoFactorEnrollResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	enabled, err := s.IsEnabled(userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := crypto.Encrypt(secret, config.Config.TwoFactor.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+
+	tf := &model.UserTwoFactor{
+		UserID:  userID,
+		Secret:  encrypted,
+		Enabled: 0,
+	}
+	if err := s.twoFactorRepo.Save(tf); err != nil {
+		return nil, err
+	}
+
+	return &model.TwoFactorEnrollResponse{
+		Secret:     secret,
+		OtpauthURL: totp.URL(config.Config.TwoFactor.Issuer, user.Username, secret),
+	}, nil
+}
+
+// Confirm 使用认证器生成的验证码确认绑定，开启两步验证并返回恢复码
+func (s *TwoFactorService) Confirm(ctx context.Context, userID int64, code string) (*model.TwoFactorRecoveryCodesResponse, error) {
+	tf, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if tf.Enabled == 1 {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	if ok, err := s.verifyTOTP(tf, code); err != nil || !ok {
+		return nil, ErrTwoFactorInvalidCode
+	}
+
+	codes, hashed, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	err = s.twoFactorRepo.UpdateFields(userID, map[string]interface{}{
+		"enabled":        1,
+		"enabled_at":     &now,
+		"recovery_codes": hashed,
+	})
+	if err != nil {
+		return nil, err
+	}
+	audit.Record(ctx, userID, audit.ActionTwoFactorEnable, audit.Target("user", userID), nil)
+
+	return &model.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable 关闭两步验证（需要验证码或恢复码）
+func (s *TwoFactorService) Disable(ctx context.Context, userID int64, code string) error {
+	if err := s.Verify(userID, code); err != nil {
+		return err
+	}
+	if err := s.twoFactorRepo.Delete(userID); err != nil {
+		return err
+	}
+	audit.Record(ctx, userID, audit.ActionTwoFactorDisable, audit.Target("user", userID), nil)
+	return nil
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码，旧的恢复码全部作废（需要验证码）
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID int64, code string) (*model.TwoFactorRecoveryCodesResponse, error) {
+	tf, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil || tf.Enabled != 1 {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	if ok, err := s.verifyTOTP(tf, code); err != nil || !ok {
+		return nil, ErrTwoFactorInvalidCode
+	}
+
+	codes, hashed, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.UpdateFields(userID, map[string]interface{}{"recovery_codes": hashed}); err != nil {
+		return nil, err
+	}
+	audit.Record(ctx, userID, audit.ActionTwoFactorRecoveryCodes, audit.Target("user", userID), nil)
+
+	return &model.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Verify 校验已开启两步验证用户的验证码，支持 TOTP 验证码和一次性恢复码
+func (s *TwoFactorService) Verify(userID int64, code string) error {
+	tf, err := s.twoFactorRepo.FindByUserID(userID)
+	if err != nil || tf.Enabled != 1 {
+		return ErrTwoFactorNotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		ok, err := s.verifyTOTP(tf, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrTwoFactorInvalidCode
+		}
+		return nil
+	}
+
+	return s.useRecoveryCode(tf, code)
+}
+
+// verifyTOTP 校验 TOTP 验证码，同一时间步的验证码只能使用一次
+func (s *TwoFactorService) verifyTOTP(tf *model.UserTwoFactor, code string) (bool, error) {
+	secret, err := crypto.Decrypt(tf.Secret, config.Config.TwoFactor.EncryptionKey)
+	if err != nil {
+		return false, err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now(), 1)
+	if !ok {
+		return false, nil
+	}
+	return s.twoFactorRepo.UseStep(tf.UserID, step)
+}
+
+// useRecoveryCode 使用一个恢复码，成功后将其从列表中移除
+func (s *TwoFactorService) useRecoveryCode(tf *model.UserTwoFactor, code string) error {
+	var hashes []string
+	if tf.RecoveryCodes != "" {
+		if err := json.Unmarshal([]byte(tf.RecoveryCodes), &hashes); err != nil {
+			return err
+		}
+	}
+
+	target := hashRecoveryCode(code)
+	for i, h := range hashes {
+		if h != target {
+			continue
+		}
+		remaining := append(hashes[:i:i], hashes[i+1:]...)
+		data, err := json.Marshal(remaining)
+		if err != nil {
+			return err
+		}
+		ok, err := s.twoFactorRepo.UseRecoveryCodes(tf.UserID, tf.RecoveryCodes, string(data))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrTwoFactorInvalidCode
+		}
+		return nil
+	}
+	return ErrTwoFactorInvalidCode
+}
+
+// generateRecoveryCodes 生成恢复码，返回明文（展示给用户）和摘要 JSON（入库）
+func generateRecoveryCodes() ([]string, string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		buf := make([]byte, 7)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, "", err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(buf))[:10]
+		code := raw[:5] + "-" + raw[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	data, err := json.Marshal(hashes)
+	if err != nil {
+		return nil, "", err
+	}
+	return codes, string(data), nil
+}
+
+// hashRecoveryCode 计算恢复码摘要（忽略大小写和分隔符）
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
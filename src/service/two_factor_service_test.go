@@ -0,0 +1,30 @@
+package service
+
+import (
+	"hi-go/src/model"
+	"hi-go/src/utils/testutil"
+	"testing"
+)
+
+func TestTwoFactorIsEnabledFailsClosed(t *testing.T) {
+	testutil.SetupConfig(t, nil)
+	s := NewTwoFactorService()
+
+	// 1. 查询失败（数据表不存在）时返回错误，而不是视为未开启
+	testutil.SetupDB(t, &model.User{})
+	if enabled, err := s.IsEnabled(1); err == nil {
+		t.Fatalf("查询失败时 IsEnabled = %v, nil，期望返回错误", enabled)
+	}
+
+	// 2. 没有记录视为未开启，已确认的记录视为开启
+	db := testutil.SetupDB(t, &model.UserTwoFactor{})
+	if enabled, err := s.IsEnabled(1); err != nil || enabled {
+		t.Fatalf("无记录时 IsEnabled = %v, %v", enabled, err)
+	}
+	if err := db.Create(&model.UserTwoFactor{UserID: 1, Secret: "secret", Enabled: 1}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if enabled, err := s.IsEnabled(1); err != nil || !enabled {
+		t.Fatalf("已开启时 IsEnabled = %v, %v", enabled, err)
+	}
+}
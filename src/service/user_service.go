@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/repository"
+	"hi-go/src/service/audit"
+	"hi-go/src/utils/passwordpolicy"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrCannotModifySelf = errors.New("不能对自己执行该操作")
+	ErrUserNotDeleted   = errors.New("用户未被删除")
+)
+
+// 用户管理业务逻辑层（管理员）
+type UserService struct {
+	userRepo    *repository.UserRepository
+	sessionRepo *repository.SessionRepository
+	roleService *RoleService
+}
+
+// 创建用户管理服务实例
+func NewUserService() *UserService {
+	return &UserService{
+		userRepo:    repository.NewUserRepository(),
+		sessionRepo: repository.NewSessionRepository(),
+		roleService: NewRoleService(),
+	}
+}
+
+// List 获取用户列表
+func (s *UserService) List(req *model.UserListRequest) (*model.UserListDataResponse, error) {
+	// 设置默认分页参数
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.PageSize <= 0 {
+		req.PageSize = config.Config.Business.DefaultPageSize
+	}
+	if req.PageSize > config.Config.Business.MaxPageSize {
+		req.PageSize = config.Config.Business.MaxPageSize
+	}
+
+	// 查询数据
+	list, total, err := s.userRepo.List(req.Keyword, req.Status, req.Deleted, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserListDataResponse{
+		List:  list,
+		Total: total,
+	}, nil
+}
+
+// GetDetail 获取用户详情（包含已删除用户）
+func (s *UserService) GetDetail(id int64) (*model.UserDetailResponse, error) {
+	user, err := s.userRepo.FindByIDUnscoped(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	roles, err := s.roleService.GetUserRoles(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &model.UserDetailResponse{
+		User:  user,
+		Roles: roles,
+	}
+	if user.DeletedAt.Valid {
+		resp.DeletedAt = &user.DeletedAt.Time
+	}
+	return resp, nil
+}
+
+// SetStatus 启用或禁用用户，禁用时立即吊销其全部会话
+func (s *UserService) SetStatus(ctx context.Context, operatorID, id int64, status int) error {
+	if operatorID == id {
+		return ErrCannotModifySelf
+	}
+	if _, err := s.userRepo.FindByID(id); err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.userRepo.UpdateFields(id, map[string]interface{}{"status": status}); err != nil {
+		return err
+	}
+	audit.Record(ctx, operatorID, audit.ActionUserStatus, audit.Target("user", id), map[string]interface{}{
+		"status": status,
+	})
+
+	if status != 1 {
+		return s.revokeSessions(id)
+	}
+	return nil
+}
+
+// ResetPassword 重置用户密码，并吊销其全部会话
+func (s *UserService) ResetPassword(ctx context.Context, operatorID, id int64, password string) error {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if err := checkPassword(password, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdateFields(id, map[string]interface{}{"password": string(hashedPassword)}); err != nil {
+		return err
+	}
+	audit.Record(ctx, operatorID, audit.ActionUserResetPassword, audit.Target("user", id), nil)
+
+	return s.revokeSessions(id)
+}
+
+// Delete 软删除用户，并吊销其全部会话
+func (s *UserService) Delete(ctx context.Context, operatorID, id int64) error {
+	if operatorID == id {
+		return ErrCannotModifySelf
+	}
+	if _, err := s.userRepo.FindByID(id); err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := s.userRepo.Delete(id); err != nil {
+		return err
+	}
+	audit.Record(ctx, operatorID, audit.ActionUserDelete, audit.Target("user", id), nil)
+
+	return s.revokeSessions(id)
+}
+
+// Restore 恢复已软删除的用户
+func (s *UserService) Restore(ctx context.Context, operatorID, id int64) error {
+	user, err := s.userRepo.FindByIDUnscoped(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	if !user.DeletedAt.Valid {
+		return ErrUserNotDeleted
+	}
+
+	if err := s.userRepo.Restore(id); err != nil {
+		return err
+	}
+	audit.Record(ctx, operatorID, audit.ActionUserRestore, audit.Target("user", id), nil)
+	return nil
+}
+
+// SetRoles 覆盖设置用户角色
+func (s *UserService) SetRoles(ctx context.Context, operatorID, id int64, roles []string) error {
+	if operatorID == id {
+		return ErrCannotModifySelf
+	}
+	if err := s.roleService.SetRoles(id, roles); err != nil {
+		return err
+	}
+	audit.Record(ctx, operatorID, audit.ActionUserRoles, audit.Target("user", id), map[string]interface{}{
+		"roles": roles,
+	})
+	return nil
+}
+
+// revokeSessions 吊销用户的全部会话，JWTAuth 随即拒绝其令牌
+func (s *UserService) revokeSessions(id int64) error {
+	return s.sessionRepo.DeleteAll(context.Background(), fmt.Sprintf("%d", id))
+}
+
+// checkPassword 按密码策略校验密码，userInputs 为用户名、邮箱等个人信息
+// 不符合策略时返回 *passwordpolicy.Error，包含逐项的失败详情
+func checkPassword(password string, userInputs ...string) error {
+	return passwordpolicy.Validate(password, userInputs...)
+}
@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+var (
+	ErrInvalidCallbackURL = errors.New("回调地址只支持 http、https")
+	ErrBlockedAddress     = errors.New("回调地址不能指向内网、回环或链路本地地址")
+)
+
+// blockedPrefixes 除 netip.Addr 自带判断（回环、私有、链路本地、组播、未指定）外需要拒绝的地址段
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // 本网络
+	netip.MustParsePrefix("100.64.0.0/10"), // 运营商级 NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF 协议分配
+	netip.MustParsePrefix("198.18.0.0/15"), // 基准测试
+	netip.MustParsePrefix("240.0.0.0/4"),   // 保留地址（含广播）
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64，可映射到内网 IPv4
+}
+
+// newDeliveryClient 创建投递使用的 HTTP 客户端
+// 回调地址由用户填写，为防止借投递访问内部服务（SSRF）：
+// 在建立连接时检查 DNS 解析后的实际 IP，不使用代理，不跟随重定向（3xx 视为投递失败）
+func newDeliveryClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: checkDialAddress,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkDialAddress 在连接前检查目标 IP，address 为解析后的 ip:port
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	if blockedAddr(ap.Addr()) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ap.Addr())
+	}
+	return nil
+}
+
+// blockedAddr 是否为不允许投递的地址
+func blockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateCallbackURL 校验回调地址：只允许 http、https，主机为 IP 时不能是内网等地址
+// 域名在投递时按解析结果检查
+func ValidateCallbackURL(raw string) error {
+	u, err := parseCallbackURL(raw)
+	if err != nil {
+		return err
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && blockedAddr(addr) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+// parseCallbackURL 解析回调地址，只允许 http、https
+func parseCallbackURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, ErrInvalidCallbackURL
+	}
+	return u, nil
+}
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hi-go/src/utils/redis"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBlockedAddr(t *testing.T) {
+	cases := map[string]bool{
+		"127.0.0.1":       true,
+		"10.1.2.3":        true,
+		"172.16.0.1":      true,
+		"192.168.1.1":     true,
+		"169.254.169.254": true,
+		"100.64.0.1":      true,
+		"0.0.0.0":         true,
+		"::1":             true,
+		"fe80::1":         true,
+		"fd00::1":         true,
+		"::ffff:10.0.0.1": true,
+		"8.8.8.8":         false,
+		"2001:4860::8888": false,
+	}
+	for ip, want := range cases {
+		if got := blockedAddr(netip.MustParseAddr(ip)); got != want {
+			t.Errorf("blockedAddr(%s) = %v，期望 %v", ip, got, want)
+		}
+	}
+}
+
+func TestValidateCallbackURL(t *testing.T) {
+	cases := map[string]error{
+		"https://example.com/hook":         nil,
+		"http://8.8.8.8:8080/hook":         nil,
+		"ftp://example.com/hook":           ErrInvalidCallbackURL,
+		"file:///etc/passwd":               ErrInvalidCallbackURL,
+		"https:///hook":                    ErrInvalidCallbackURL,
+		"http://169.254.169.254/latest":    ErrBlockedAddress,
+		"http://127.0.0.1:6379/":           ErrBlockedAddress,
+		"http://[::1]/hook":                ErrBlockedAddress,
+		"http://[::ffff:192.168.0.1]/hook": ErrBlockedAddress,
+	}
+	for raw, want := range cases {
+		if err := ValidateCallbackURL(raw); !errors.Is(err, want) {
+			t.Errorf("ValidateCallbackURL(%s) = %v，期望 %v", raw, err, want)
+		}
+	}
+}
+
+func TestDeliveryClientRejectsInternalAddress(t *testing.T) {
+	var hit atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { hit.Store(true) }))
+	t.Cleanup(srv.Close)
+
+	// 域名解析到回环地址同样拒绝
+	u, _ := url.Parse(srv.URL)
+	for _, target := range []string{srv.URL, "http://localhost:" + u.Port()} {
+		_, err := newDeliveryClient(5 * time.Second).Get(target)
+		if !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("GET %s err = %v，期望 ErrBlockedAddress", target, err)
+		}
+	}
+	if hit.Load() {
+		t.Error("请求到达了内网地址")
+	}
+}
+
+func TestDeliveryClientDoesNotFollowRedirects(t *testing.T) {
+	var hit atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/internal", http.StatusFound)
+	})
+	mux.HandleFunc("/internal", func(w http.ResponseWriter, r *http.Request) { hit.Store(true) })
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	// 测试服务器在回环地址上，换用默认连接方式只检查重定向策略
+	client := newDeliveryClient(5 * time.Second)
+	client.Transport = http.DefaultTransport
+	resp, err := client.Get(srv.URL + "/hook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound || hit.Load() {
+		t.Fatalf("状态码 = %d, 跟随重定向 = %v", resp.StatusCode, hit.Load())
+	}
+}
+
+func TestDispatchAbandonsBlockedAddress(t *testing.T) {
+	r, _ := setupDispatcher(t)
+	ctx := context.Background()
+	id := publishOne(t)
+
+	// 回调地址指向回环地址，第一次投递即放弃，不再重试
+	if n := dispatch(ctx, newDeliveryClient(5*time.Second), 10, time.Minute); n != 1 {
+		t.Fatalf("dispatch = %d，期望 1", n)
+	}
+	if len(r.deliveries) != 0 {
+		t.Error("接收方不应收到请求")
+	}
+	if n := redis.Client.ZCard(ctx, queueKey).Val(); n != 0 {
+		t.Errorf("队列剩余 %d 个任务", n)
+	}
+	dead := redis.Client.LRange(ctx, deadKey, 0, -1).Val()
+	if len(dead) != 1 {
+		t.Fatalf("失败列表 = %v", dead)
+	}
+	var j job
+	if err := json.Unmarshal([]byte(dead[0]), &j); err != nil {
+		t.Fatal(err)
+	}
+	if j.ID != id || j.Attempts != 1 {
+		t.Errorf("失败列表中的任务 = %+v", j)
+	}
+}
+
+func TestValidEvent(t *testing.T) {
+	if !ValidEvent(EventHomeUpdated) || ValidEvent("home.*") || ValidEvent("") {
+		t.Fatal("ValidEvent 结果错误")
+	}
+}
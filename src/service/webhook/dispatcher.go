@@ -0,0 +1,260 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hi-go/src/config"
+	"hi-go/src/utils/logger"
+	"hi-go/src/utils/redis"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// leaseMargin 任务被领取后在投递超时之外额外保留的时间
+// 领取时把任务的下次投递时间推迟到租约到期，进程在投递途中退出时任务到期后会被重新领取
+const leaseMargin = time.Minute
+
+// errWebhookGone webhook 已删除或禁用，任务不再投递
+var errWebhookGone = errors.New("webhook 已删除或禁用")
+
+// claimScript 原子地领取到期任务：取出分值不大于当前时间的任务，并把分值改为租约到期时间
+// 多实例部署时同一任务只会被一个实例领取
+var claimScript = goredis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[3])
+for _, id in ipairs(ids) do
+	redis.call('ZADD', KEYS[1], ARGV[2], id)
+end
+return ids
+`)
+
+// StartDispatcher 启动 webhook 投递任务，持续从 Redis 队列领取到期任务并投递
+// 未配置并发数时不启动（事件仍会入队，启动后补发）；ctx 取消后任务退出
+func StartDispatcher(ctx context.Context) {
+	workers := config.Config.Webhook.Workers
+	interval := config.GetWebhookPollInterval()
+	timeout := config.GetWebhookDeliveryTimeout()
+	if workers <= 0 || interval <= 0 {
+		logger.Info("webhook 投递任务未开启")
+		return
+	}
+
+	client := newDeliveryClient(timeout)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			// 领满一批说明可能还有到期任务，不等待直接领取下一批
+			if n := dispatch(ctx, client, workers, timeout+leaseMargin); n < workers {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			} else if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+	logger.Info("webhook 投递任务已启动",
+		zap.Int("workers", workers),
+		zap.Duration("timeout", timeout))
+}
+
+// dispatch 领取一批到期任务并发投递，返回领取的任务数
+func dispatch(ctx context.Context, client *http.Client, limit int, lease time.Duration) int {
+	if redis.Client == nil {
+		return 0
+	}
+
+	now := time.Now()
+	ids, err := claimScript.Run(ctx, redis.Client, []string{queueKey},
+		now.UnixMilli(), now.Add(lease).UnixMilli(), limit).StringSlice()
+	if err != nil {
+		logger.Warn("领取 webhook 投递任务失败", zap.Error(err))
+		return 0
+	}
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			process(ctx, client, id)
+		}(id)
+	}
+	wg.Wait()
+	return len(ids)
+}
+
+// process 投递一个任务，成功后删除，失败后按退避间隔重新入队，超过最大次数后放弃
+func process(ctx context.Context, client *http.Client, id string) {
+	// 1. 读取任务
+	data, err := redis.HGet(ctx, jobsKey, id)
+	if errors.Is(err, redis.ErrKeyNotFound) {
+		// 任务详情已不存在，清理队列中的残留
+		redis.ZRem(ctx, queueKey, id)
+		return
+	}
+	if err != nil {
+		logger.Warn("读取 webhook 投递任务失败", zap.String("jobID", id), zap.Error(err))
+		return
+	}
+	var j job
+	if err := json.Unmarshal([]byte(data), &j); err != nil {
+		logger.Error("webhook 投递任务格式错误，已丢弃", zap.String("jobID", id), zap.Error(err))
+		finish(ctx, id)
+		return
+	}
+
+	// 2. 投递
+	j.Attempts++
+	err = deliver(ctx, client, &j)
+	if err == nil {
+		finish(ctx, id)
+		return
+	}
+	if errors.Is(err, errWebhookGone) {
+		logger.Info("webhook 已删除或禁用，取消投递",
+			zap.String("jobID", id),
+			zap.Int64("webhookID", j.WebhookID))
+		finish(ctx, id)
+		return
+	}
+
+	// 3. 失败后重试或放弃，回调地址不允许投递时重试也不会成功，直接放弃
+	j.LastError = err.Error()
+	if j.Attempts >= config.Config.Webhook.MaxAttempts ||
+		errors.Is(err, ErrInvalidCallbackURL) || errors.Is(err, ErrBlockedAddress) {
+		logger.Error("webhook 投递失败，已放弃",
+			zap.String("jobID", id),
+			zap.Int64("webhookID", j.WebhookID),
+			zap.String("event", j.Event),
+			zap.Int("attempts", j.Attempts),
+			zap.Error(err))
+		abandon(ctx, &j)
+		return
+	}
+
+	delay := retryDelay(j.Attempts)
+	logger.Warn("webhook 投递失败，稍后重试",
+		zap.String("jobID", id),
+		zap.Int64("webhookID", j.WebhookID),
+		zap.Int("attempts", j.Attempts),
+		zap.Duration("retryIn", delay),
+		zap.Error(err))
+	retry(ctx, &j, time.Now().Add(delay))
+}
+
+// deliver 签名并发送请求，2xx 响应视为成功
+func deliver(ctx context.Context, client *http.Client, j *job) error {
+	w, err := webhookRepo.FindByID(j.WebhookID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errWebhookGone
+	}
+	if err != nil {
+		return err
+	}
+	if w.Enabled != 1 {
+		return errWebhookGone
+	}
+	// 目标地址在建立连接时检查（见 newDeliveryClient）
+	if _, err := parseCallbackURL(w.CallbackURL); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.CallbackURL, bytes.NewReader(j.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerSign, Sign(j.Body, w.Secret))
+	req.Header.Set("X-Webhook-Event", j.Event)
+	req.Header.Set("X-Webhook-Delivery", j.ID)
+	req.Header.Set("X-Webhook-Attempt", strconv.Itoa(j.Attempts))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 读完响应体以便复用连接，只读取有限长度
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("响应状态码 %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// retryDelay 计算第 attempts 次失败后的重试间隔
+// 基础间隔每次翻倍，不超过上限；取 [间隔/2, 间隔] 之间的随机值，避免大量任务同时重试
+func retryDelay(attempts int) time.Duration {
+	base := config.GetWebhookRetryBaseDelay()
+	maxDelay := config.GetWebhookRetryMaxDelay()
+
+	delay := maxDelay
+	if shift := attempts - 1; shift < 32 {
+		if d := base << shift; d > 0 && d < maxDelay {
+			delay = d
+		}
+	}
+
+	half := delay / 2
+	if half <= 0 {
+		return delay
+	}
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// finish 删除已完成的任务
+func finish(ctx context.Context, id string) {
+	_, err := redis.TxPipeline(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, queueKey, id)
+		pipe.HDel(ctx, jobsKey, id)
+		return nil
+	})
+	if err != nil {
+		logger.Warn("删除 webhook 投递任务失败", zap.String("jobID", id), zap.Error(err))
+	}
+}
+
+// retry 保存投递次数和失败原因，并在 at 时重新投递
+func retry(ctx context.Context, j *job, at time.Time) {
+	data, _ := json.Marshal(j)
+	_, err := redis.TxPipeline(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.HSet(ctx, jobsKey, j.ID, data)
+		pipe.ZAdd(ctx, queueKey, goredis.Z{Score: float64(at.UnixMilli()), Member: j.ID})
+		return nil
+	})
+	if err != nil {
+		// 租约到期后任务会被重新领取
+		logger.Warn("webhook 投递任务重新入队失败", zap.String("jobID", j.ID), zap.Error(err))
+	}
+}
+
+// abandon 放弃任务，移入失败列表备查
+func abandon(ctx context.Context, j *job) {
+	data, _ := json.Marshal(j)
+	_, err := redis.TxPipeline(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.ZRem(ctx, queueKey, j.ID)
+		pipe.HDel(ctx, jobsKey, j.ID)
+		pipe.LPush(ctx, deadKey, data)
+		pipe.LTrim(ctx, deadKey, 0, deadKeep-1)
+		return nil
+	})
+	if err != nil {
+		logger.Warn("webhook 投递任务移入失败列表失败", zap.String("jobID", j.ID), zap.Error(err))
+	}
+}
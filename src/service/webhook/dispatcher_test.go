@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"hi-go/src/config"
+	"hi-go/src/model"
+	"hi-go/src/utils/mysql"
+	"hi-go/src/utils/redis"
+	"hi-go/src/utils/testutil"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// delivery 接收方收到的一次投递
+type delivery struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver 记录收到的请求，并按 status 返回响应状态码
+type receiver struct {
+	*httptest.Server
+	mu         sync.Mutex
+	status     int
+	deliveries []delivery
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{status: http.StatusOK}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.deliveries = append(r.deliveries, delivery{header: req.Header.Clone(), body: body})
+		w.WriteHeader(r.status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) setStatus(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *receiver) last(t *testing.T) delivery {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.deliveries) == 0 {
+		t.Fatal("接收方未收到请求")
+	}
+	return r.deliveries[len(r.deliveries)-1]
+}
+
+// setupDispatcher 准备配置、数据库、Redis 和一个订阅 home.updated 的 webhook
+func setupDispatcher(t *testing.T) (*receiver, *model.Webhook) {
+	testutil.SetupConfig(t, func(cfg *config.AppConfig) {
+		cfg.Webhook = config.WebhookConfig{
+			Workers:         4,
+			DeliveryTimeout: 5,
+			MaxAttempts:     3,
+			RetryBaseDelay:  10,
+			RetryMaxDelay:   60,
+		}
+	})
+	db := testutil.SetupDB(t, &model.Webhook{})
+	testutil.SetupRedis(t)
+
+	r := newReceiver(t)
+	w := &model.Webhook{
+		ID:          1,
+		Name:        "test",
+		CallbackURL: r.URL,
+		Event:       EventHomeUpdated,
+		Secret:      "test-secret",
+		Enabled:     1,
+		UserID:      1,
+	}
+	if err := db.Create(w).Error; err != nil {
+		t.Fatal(err)
+	}
+	return r, w
+}
+
+// publishOne 发布一个事件并返回投递任务ID
+func publishOne(t *testing.T) string {
+	t.Helper()
+	ctx := context.Background()
+	if err := Publish(ctx, EventHomeUpdated, &HomeEvent{ID: 7, Title: "轮播", OperatorID: 1}); err != nil {
+		t.Fatalf("Publish 失败: %v", err)
+	}
+	ids, err := redis.Client.ZRange(ctx, queueKey, 0, -1).Result()
+	if err != nil || len(ids) != 1 {
+		t.Fatalf("队列 = %v, err = %v，期望 1 个任务", ids, err)
+	}
+	return ids[0]
+}
+
+// makeDue 把任务的下次投递时间改为现在
+func makeDue(t *testing.T, id string) {
+	t.Helper()
+	err := redis.Client.ZAdd(context.Background(), queueKey, goredis.Z{Score: float64(time.Now().UnixMilli()), Member: id}).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// loadJob 读取保存在 Redis 中的任务
+func loadJob(t *testing.T, id string) *job {
+	t.Helper()
+	data, err := redis.HGet(context.Background(), jobsKey, id)
+	if err != nil {
+		t.Fatalf("读取任务失败: %v", err)
+	}
+	var j job
+	if err := json.Unmarshal([]byte(data), &j); err != nil {
+		t.Fatal(err)
+	}
+	return &j
+}
+
+func TestDispatchDelivers(t *testing.T) {
+	r, w := setupDispatcher(t)
+	ctx := context.Background()
+	id := publishOne(t)
+
+	if n := dispatch(ctx, r.Client(), 10, time.Minute); n != 1 {
+		t.Fatalf("dispatch = %d，期望 1", n)
+	}
+
+	// 1. 请求头携带签名、事件名称、任务ID和投递次数
+	d := r.last(t)
+	if got, want := d.header.Get(headerSign), Sign(d.body, w.Secret); got != want {
+		t.Errorf("签名 = %q，期望 %q", got, want)
+	}
+	if d.header.Get("X-Webhook-Event") != EventHomeUpdated ||
+		d.header.Get("X-Webhook-Delivery") != id ||
+		d.header.Get("X-Webhook-Attempt") != "1" {
+		t.Errorf("请求头 = %v", d.header)
+	}
+
+	// 2. 请求体为事件信封
+	var env struct {
+		Event string    `json:"event"`
+		Data  HomeEvent `json:"data"`
+	}
+	if err := json.Unmarshal(d.body, &env); err != nil {
+		t.Fatal(err)
+	}
+	if env.Event != EventHomeUpdated || env.Data.ID != 7 || env.Data.Title != "轮播" {
+		t.Errorf("请求体 = %s", d.body)
+	}
+
+	// 3. 成功后删除任务
+	if n := redis.Client.ZCard(ctx, queueKey).Val(); n != 0 {
+		t.Errorf("队列剩余 %d 个任务", n)
+	}
+	if n := redis.Client.HLen(ctx, jobsKey).Val(); n != 0 {
+		t.Errorf("任务详情剩余 %d 条", n)
+	}
+}
+
+func TestDispatchRetriesThenAbandons(t *testing.T) {
+	r, _ := setupDispatcher(t)
+	r.setStatus(http.StatusInternalServerError)
+	ctx := context.Background()
+	id := publishOne(t)
+
+	// 1. 失败后按 retryDelay 重新入队：首次重试在基础间隔的 [1/2, 1] 之间
+	before := time.Now().Truncate(time.Millisecond) // 分值精确到毫秒
+	if n := dispatch(ctx, r.Client(), 10, time.Minute); n != 1 {
+		t.Fatalf("dispatch = %d，期望 1", n)
+	}
+	score, err := redis.Client.ZScore(ctx, queueKey, id).Result()
+	if err != nil {
+		t.Fatalf("任务未重新入队: %v", err)
+	}
+	at := time.UnixMilli(int64(score))
+	if at.Before(before.Add(5*time.Second)) || at.After(time.Now().Add(10*time.Second)) {
+		t.Errorf("下次投递时间 %v 不在 [5s, 10s] 之后", at.Sub(before))
+	}
+	j := loadJob(t, id)
+	if j.Attempts != 1 || j.LastError == "" {
+		t.Errorf("任务 = %+v", j)
+	}
+
+	// 2. 未到期的任务不会被领取
+	if n := dispatch(ctx, r.Client(), 10, time.Minute); n != 0 {
+		t.Fatalf("未到期时 dispatch = %d，期望 0", n)
+	}
+
+	// 3. 达到最大投递次数后放弃，移入失败列表
+	for attempt := 2; attempt <= 3; attempt++ {
+		makeDue(t, id)
+		if n := dispatch(ctx, r.Client(), 10, time.Minute); n != 1 {
+			t.Fatalf("第 %d 次 dispatch = %d，期望 1", attempt, n)
+		}
+	}
+	if got := r.last(t).header.Get("X-Webhook-Attempt"); got != "3" {
+		t.Errorf("最后一次投递次数 = %s，期望 3", got)
+	}
+	if redis.Client.ZCard(ctx, queueKey).Val() != 0 || redis.Client.HLen(ctx, jobsKey).Val() != 0 {
+		t.Error("放弃后任务仍在队列中")
+	}
+	dead, err := redis.Client.LRange(ctx, deadKey, 0, -1).Result()
+	if err != nil || len(dead) != 1 {
+		t.Fatalf("失败列表 = %v, err = %v", dead, err)
+	}
+	var abandoned job
+	if err := json.Unmarshal([]byte(dead[0]), &abandoned); err != nil {
+		t.Fatal(err)
+	}
+	if abandoned.ID != id || abandoned.Attempts != 3 {
+		t.Errorf("失败列表中的任务 = %+v", abandoned)
+	}
+}
+
+func TestDispatchSkipsDisabledWebhook(t *testing.T) {
+	r, w := setupDispatcher(t)
+	ctx := context.Background()
+	publishOne(t)
+
+	if err := mysql.Database.Model(w).Update("enabled", 0).Error; err != nil {
+		t.Fatal(err)
+	}
+	if n := dispatch(ctx, r.Client(), 10, time.Minute); n != 1 {
+		t.Fatalf("dispatch = %d，期望 1", n)
+	}
+	if len(r.deliveries) != 0 {
+		t.Error("已禁用的 webhook 仍收到请求")
+	}
+	if redis.Client.ZCard(ctx, queueKey).Val() != 0 || redis.Client.LLen(ctx, deadKey).Val() != 0 {
+		t.Error("已禁用的 webhook 的任务应直接删除")
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	testutil.SetupConfig(t, func(cfg *config.AppConfig) {
+		cfg.Webhook.RetryBaseDelay = 10
+		cfg.Webhook.RetryMaxDelay = 60
+	})
+
+	cases := []struct {
+		attempts int
+		max      time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{4, 60 * time.Second}, // 超过上限
+		{64, 60 * time.Second},
+	}
+	for _, tc := range cases {
+		for i := 0; i < 100; i++ {
+			d := retryDelay(tc.attempts)
+			if d < tc.max/2 || d > tc.max {
+				t.Fatalf("retryDelay(%d) = %v，期望在 [%v, %v] 之间", tc.attempts, d, tc.max/2, tc.max)
+			}
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package webhook
+
+import "slices"
+
+// 出站事件名称，webhook 的 event 字段与之相同时会收到对应事件
+const (
+	EventHomeUpdated  = "home.updated"  // 首页内容已更新
+	EventHomeDeleted  = "home.deleted"  // 首页内容已移入回收站
+	EventHomeRestored = "home.restored" // 首页内容已从回收站恢复
+	EventHomePurged   = "home.purged"   // 首页内容已彻底删除
+)
+
+// events 全部出站事件
+var events = []string{
+	EventHomeUpdated,
+	EventHomeDeleted,
+	EventHomeRestored,
+	EventHomePurged,
+}
+
+// ValidEvent 是否为可订阅的出站事件
+func ValidEvent(event string) bool {
+	return slices.Contains(events, event)
+}
+
+// HomeEvent 首页内容事件的数据（envelope.data）
+type HomeEvent struct {
+	ID         int64                  `json:"id"`                // 首页内容ID
+	Title      string                 `json:"title"`             // 标题（更新事件为更新前的标题）
+	OperatorID int64                  `json:"operator_id"`       // 操作人ID
+	Changes    map[string]interface{} `json:"changes,omitempty"` // 更新的字段，仅 home.updated 携带
+}
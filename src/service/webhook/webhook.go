@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hi-go/src/repository"
+	"hi-go/src/utils/redis"
+	"hi-go/src/utils/snowflake"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	queueKey   = "webhook:delivery:queue" // 待投递任务 ZSET，分值为下次投递时间（毫秒时间戳）
+	jobsKey    = "webhook:delivery:jobs"  // 投递任务详情 Hash，字段为任务ID
+	deadKey    = "webhook:delivery:dead"  // 超过最大投递次数后放弃的任务（只保留最近的 deadKeep 条）
+	deadKeep   = 1000
+	headerSign = "X-Webhook-Signature"
+)
+
+var webhookRepo = repository.NewWebhookRepository()
+
+// envelope 投递的请求体，同一事件发给不同 webhook 的请求体相同
+type envelope struct {
+	ID        string      `json:"id"`         // 事件ID
+	Event     string      `json:"event"`      // 事件名称
+	CreatedAt time.Time   `json:"created_at"` // 事件发生时间
+	Data      interface{} `json:"data"`       // 事件数据
+}
+
+// job 投递任务，保存在 Redis 中，进程重启后继续投递
+// 不保存密钥和回调地址，投递时重新读取 webhook，修改或禁用后立即生效
+type job struct {
+	ID        string          `json:"id"`                   // 任务ID，同时作为 X-Webhook-Delivery 请求头
+	WebhookID int64           `json:"webhook_id"`           // webhook ID
+	Event     string          `json:"event"`                // 事件名称
+	Body      json.RawMessage `json:"body"`                 // 请求体
+	Attempts  int             `json:"attempts"`             // 已投递次数
+	LastError string          `json:"last_error,omitempty"` // 最近一次投递失败的原因
+	CreatedAt time.Time       `json:"created_at"`           // 入队时间
+}
+
+// Publish 发布事件：为订阅了该事件的每个已启用 webhook 创建一个投递任务
+// 只负责入队，由 StartDispatcher 启动的投递任务异步发送；没有订阅者时直接返回
+func Publish(ctx context.Context, event string, payload interface{}) error {
+	// 1. 查找订阅者
+	webhooks, err := webhookRepo.FindEnabledByEvent(event)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	// 2. 生成请求体
+	now := time.Now()
+	body, err := json.Marshal(envelope{
+		ID:        strconv.FormatInt(snowflake.MustGenerate(), 10),
+		Event:     event,
+		CreatedAt: now,
+		Data:      payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 3. 每个订阅者一个任务，同一事务中入队
+	_, err = redis.TxPipeline(ctx, func(pipe goredis.Pipeliner) error {
+		for _, w := range webhooks {
+			j := &job{
+				ID:        strconv.FormatInt(snowflake.MustGenerate(), 10),
+				WebhookID: w.ID,
+				Event:     event,
+				Body:      body,
+				CreatedAt: now,
+			}
+			data, err := json.Marshal(j)
+			if err != nil {
+				return err
+			}
+			pipe.HSet(ctx, jobsKey, j.ID, data)
+			pipe.ZAdd(ctx, queueKey, goredis.Z{Score: float64(now.UnixMilli()), Member: j.ID})
+		}
+		return nil
+	})
+	return err
+}
+
+// Sign 生成 HMAC-SHA256 签名（十六进制），放在 X-Webhook-Signature 请求头中
+func Sign(body []byte, secret string) string {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -1,17 +1,25 @@
 package service
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/rand"
-	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"hi-go/src/model"
 	"hi-go/src/repository"
+	"hi-go/src/service/audit"
+	"hi-go/src/service/webhook"
 	"hi-go/src/utils/logger"
 	"hi-go/src/utils/snowflake"
 )
 
+var (
+	ErrWebhookInvalidEvent       = errors.New("不支持订阅该事件")
+	ErrWebhookInvalidCallbackURL = errors.New("回调地址无效")
+)
+
 // WebhookService Webhook 业务逻辑层
 type WebhookService struct {
 	webhookRepo *repository.WebhookRepository
@@ -25,7 +33,11 @@ func NewWebhookService() *WebhookService {
 }
 
 // Create 创建 webhook（返回包含 secret 的响应）
-func (s *WebhookService) Create(req *model.WebhookCreateRequest, userID int64) (*model.WebhookResponseWithSecret, error) {
+func (s *WebhookService) Create(ctx context.Context, req *model.WebhookCreateRequest, userID int64) (*model.WebhookResponseWithSecret, error) {
+	if err := validateWebhook(req.CallbackURL, req.Event); err != nil {
+		return nil, err
+	}
+
 	// 生成唯一 secret
 	secret, err := generateSecret()
 	if err != nil {
@@ -56,12 +68,22 @@ func (s *WebhookService) Create(req *model.WebhookCreateRequest, userID int64) (
 	if err := s.webhookRepo.Create(webhook); err != nil {
 		return nil, fmt.Errorf("创建 webhook 失败: %v", err)
 	}
+	audit.Record(ctx, userID, audit.ActionWebhookCreate, audit.Target("webhook", webhook.ID), map[string]interface{}{
+		"name":         webhook.Name,
+		"callback_url": webhook.CallbackURL,
+		"event":        webhook.Event,
+		"enabled":      webhook.Enabled,
+	})
 
 	return webhook.ToResponseWithSecret(), nil
 }
 
 // Update 更新 webhook
-func (s *WebhookService) Update(req *model.WebhookUpdateRequest, userID int64) (*model.WebhookResponse, error) {
+func (s *WebhookService) Update(ctx context.Context, req *model.WebhookUpdateRequest, userID int64) (*model.WebhookResponse, error) {
+	if err := validateWebhook(req.CallbackURL, req.Event); err != nil {
+		return nil, err
+	}
+
 	// 1. 检查 webhook 是否存在
 	webhook, err := s.webhookRepo.FindByID(req.ID)
 	if err != nil {
@@ -112,12 +134,13 @@ func (s *WebhookService) Update(req *model.WebhookUpdateRequest, userID int64) (
 	if err := s.webhookRepo.Update(webhook); err != nil {
 		return nil, fmt.Errorf("更新 webhook 失败: %v", err)
 	}
+	audit.Record(ctx, userID, audit.ActionWebhookUpdate, audit.Target("webhook", webhook.ID), updates)
 
 	return webhook.ToResponse(), nil
 }
 
 // Delete 删除 webhook
-func (s *WebhookService) Delete(id int64, userID int64) error {
+func (s *WebhookService) Delete(ctx context.Context, id int64, userID int64) error {
 	// 1. 检查 webhook 是否存在
 	webhook, err := s.webhookRepo.FindByID(id)
 	if err != nil {
@@ -130,7 +153,13 @@ func (s *WebhookService) Delete(id int64, userID int64) error {
 	}
 
 	// 3. 执行删除
-	return s.webhookRepo.Delete(id)
+	if err := s.webhookRepo.Delete(id); err != nil {
+		return err
+	}
+	audit.Record(ctx, userID, audit.ActionWebhookDelete, audit.Target("webhook", id), map[string]interface{}{
+		"name": webhook.Name,
+	})
+	return nil
 }
 
 // GetByID 根据 ID 获取 webhook
@@ -195,15 +224,13 @@ func generateSecret() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-// generateSignature 生成 HMAC-SHA256 签名
+// generateSignature 生成 HMAC-SHA256 签名，与出站投递使用相同的算法
 func generateSignature(body []byte, secret string) string {
-	h := hmac.New(sha256.New, []byte(secret))
-	h.Write(body)
-	return hex.EncodeToString(h.Sum(nil))
+	return webhook.Sign(body, secret)
 }
 
 // Sign 生成签名（供 API 调用）
-func (s *WebhookService) Sign(req *model.WebhookSignRequest, userID int64) (*model.WebhookSignResponse, error) {
+func (s *WebhookService) Sign(ctx context.Context, req *model.WebhookSignRequest, userID int64) (*model.WebhookSignResponse, error) {
 	// 1. 检查 webhook 是否存在
 	webhook, err := s.webhookRepo.FindByID(req.ID)
 	if err != nil {
@@ -219,6 +246,7 @@ func (s *WebhookService) Sign(req *model.WebhookSignRequest, userID int64) (*mod
 
 	// 3. 生成签名
 	signature := generateSignature([]byte(req.Body), webhook.Secret)
+	audit.Record(ctx, userID, audit.ActionWebhookSign, audit.Target("webhook", webhook.ID), nil)
 
 	return &model.WebhookSignResponse{
 		Signature: signature,
@@ -227,3 +255,16 @@ func (s *WebhookService) Sign(req *model.WebhookSignRequest, userID int64) (*mod
 		Secret:    webhook.Secret,
 	}, nil
 }
+
+// validateWebhook 校验回调地址和订阅的事件，为空表示不修改
+func validateWebhook(callbackURL, event string) error {
+	if event != "" && !webhook.ValidEvent(event) {
+		return fmt.Errorf("%w: %s", ErrWebhookInvalidEvent, event)
+	}
+	if callbackURL != "" {
+		if err := webhook.ValidateCallbackURL(callbackURL); err != nil {
+			return fmt.Errorf("%w: %v", ErrWebhookInvalidCallbackURL, err)
+		}
+	}
+	return nil
+}
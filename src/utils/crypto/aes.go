@@ -0,0 +1,93 @@
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+)
+
+// 错误定义
+var (
+	ErrKeyEmpty         = errors.New("crypto: 密钥不能为空")
+	ErrCiphertextFormat = errors.New("crypto: 密文格式错误")
+)
+
+// deriveKey 将任意长度的密钥字符串派生为 AES-256 密钥
+func deriveKey(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+	return sum[:]
+}
+
+//	使用 AES-256-GCM 加密字符串
+//
+// 参数:
+//   - plaintext: 明文
+//   - key: 密钥字符串（内部经 SHA-256 派生）
+//
+// 返回:
+//   - string: Base64 编码的 nonce+密文
+//   - error: 错误信息
+func Encrypt(plaintext, key string) (string, error) {
+	if key == "" {
+		return "", ErrKeyEmpty
+	}
+
+	block, err := aes.NewCipher(deriveKey(key))
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+//	解密 Encrypt 生成的密文
+//
+// 参数:
+//   - ciphertext: Base64 编码的 nonce+密文
+//   - key: 加密时使用的密钥字符串
+//
+// 返回:
+//   - string: 明文
+//   - error: 错误信息
+func Decrypt(ciphertext, key string) (string, error) {
+	if key == "" {
+		return "", ErrKeyEmpty
+	}
+
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", ErrCiphertextFormat
+	}
+
+	block, err := aes.NewCipher(deriveKey(key))
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return "", ErrCiphertextFormat
+	}
+	nonce, sealed := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
@@ -0,0 +1,107 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/elastic/go-elasticsearch/v8/esapi"
+)
+
+// Client 全局 Elasticsearch 客户端，用于业务数据检索（与日志写入使用的客户端相互独立）
+// 未初始化时为 nil，调用方应回退到其他查询方式
+var Client *elasticsearch.Client
+
+// ErrNotInitialized 客户端未初始化
+var ErrNotInitialized = errors.New("elasticsearch client not initialized")
+
+// ClientConfig Elasticsearch 客户端配置
+type ClientConfig struct {
+	Addrs    []string // ES 集群地址
+	Username string   // 用户名（可选）
+	Password string   // 密码（可选）
+	MaxRetry int      // 最大重试次数
+}
+
+// ResponseError ES 返回的错误响应
+type ResponseError struct {
+	StatusCode int    // HTTP 状态码
+	Body       string // 响应内容
+}
+
+// Error 实现 error 接口
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("elasticsearch returned %d: %s", e.StatusCode, e.Body)
+}
+
+// InitClient 初始化全局客户端并测试连接
+func InitClient(cfg *ClientConfig) error {
+	if cfg == nil || len(cfg.Addrs) == 0 {
+		return fmt.Errorf("elasticsearch addresses cannot be empty")
+	}
+
+	client, err := elasticsearch.NewClient(elasticsearch.Config{
+		Addresses:  cfg.Addrs,
+		Username:   cfg.Username,
+		Password:   cfg.Password,
+		MaxRetries: cfg.MaxRetry,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create elasticsearch client: %w", err)
+	}
+
+	// 测试连接
+	res, err := client.Info()
+	if err != nil {
+		return fmt.Errorf("failed to connect to elasticsearch: %w", err)
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch returned error: %s", res.String())
+	}
+
+	Client = client
+	return nil
+}
+
+// Do 执行请求，响应为错误时返回 *ResponseError；result 不为 nil 时解析响应 JSON
+func Do(ctx context.Context, req esapi.Request, result interface{}) error {
+	if Client == nil {
+		return ErrNotInitialized
+	}
+
+	res, err := req.Do(ctx, Client)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := io.ReadAll(res.Body)
+		return &ResponseError{StatusCode: res.StatusCode, Body: string(body)}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// IsNotFound 是否为 404 错误（索引或文档不存在）
+func IsNotFound(err error) bool {
+	var resErr *ResponseError
+	return errors.As(err, &resErr) && resErr.StatusCode == http.StatusNotFound
+}
+
+// JSONBody 将请求体编码为 JSON
+func JSONBody(v interface{}) (io.Reader, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
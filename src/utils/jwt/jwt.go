@@ -3,9 +3,11 @@ package jwt
 import (
 	"errors"
 	"hi-go/src/config"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // Manager 全局JWT管理器实例
@@ -18,46 +20,88 @@ var (
 	ErrTokenMalformed   = errors.New("token格式错误")
 	ErrTokenNotValidYet = errors.New("token尚未生效")
 	ErrSecretKeyEmpty   = errors.New("密钥不能为空")
+	ErrNotRefreshToken  = errors.New("token不是刷新令牌")
 )
 
+// 令牌类型
+const (
+	TokenTypeAccess  = "access"  // 访问令牌
+	TokenTypeRefresh = "refresh" // 刷新令牌
+)
+
+// 代登录令牌写入 Claims.Extra 的字段
+const (
+	ExtraActor        = "act"          // 实际操作人（管理员），参考 RFC 8693 的 act 声明
+	ExtraImpersonated = "impersonated" // 代登录标记，值为 true
+)
+
+// Actor 代登录令牌中的实际操作人
+type Actor struct {
+	UserID   string `json:"sub"`      // 管理员用户ID
+	Username string `json:"username"` // 管理员用户名
+}
+
 // Claims 自定义JWT载荷结构
 type Claims struct {
-	UserID               string                 `json:"user_id"`  // 用户ID
-	Username             string                 `json:"username"` // 用户名
-	Roles                []string               `json:"roles"`    // 用户角色列表
-	Extra                map[string]interface{} `json:"extra"`    // 额外的自定义字段
+	UserID               string                 `json:"user_id"`    // 用户ID
+	Username             string                 `json:"username"`   // 用户名
+	Roles                []string               `json:"roles"`      // 用户角色列表
+	Extra                map[string]interface{} `json:"extra"`      // 额外的自定义字段
+	TokenType            string                 `json:"token_type"` // 令牌类型：access / refresh
+	SessionID            string                 `json:"sid"`        // 会话ID，同一次登录签发的令牌共用
 	jwt.RegisteredClaims                        // JWT标准声明
 }
 
 // JWT配置
 type Config struct {
-	SecretKey            string        // 签名密钥
+	SecretKey            string        // 签名密钥（HS256）
 	AccessTokenDuration  time.Duration // 访问令牌过期时间
 	RefreshTokenDuration time.Duration // 刷新令牌过期时间
 	Issuer               string        // 签发者
+	Algorithm            string        // 签名算法：HS256（默认）、RS256、ES256、EdDSA
+	SigningKeyID         string        // 当前用于签名的密钥ID（非对称算法时必填）
+	Keys                 []KeyConfig   // 非对称密钥列表，未过期的密钥均可用于验证
 }
 
 // 返回默认配置
 func DefaultConfig() *Config {
+	keys := make([]KeyConfig, 0, len(config.Config.JWT.Keys))
+	for _, k := range config.Config.JWT.Keys {
+		keys = append(keys, KeyConfig{
+			ID:             k.ID,
+			Algorithm:      k.Algorithm,
+			PrivateKeyFile: k.PrivateKeyFile,
+			PublicKeyFile:  k.PublicKeyFile,
+			ExpiresAt:      k.ExpiresAt,
+		})
+	}
+
 	return &Config{
 		SecretKey:            config.JWTSecretKey,                 // 使用配置常量
 		AccessTokenDuration:  config.GetJWTAccessTokenDuration(),  // 访问令牌
 		RefreshTokenDuration: config.GetJWTRefreshTokenDuration(), // 刷新令牌
 		Issuer:               config.JWTIssuer,                    // 签发者
+		Algorithm:            config.Config.JWT.Algorithm,         // 签名算法
+		SigningKeyID:         config.Config.JWT.SigningKeyID,      // 签名密钥ID
+		Keys:                 keys,                                // 非对称密钥
 	}
 }
 
 // JWT管理器
 type JWTManager struct {
-	config *Config
+	config  *Config
+	keys    map[string]*signingKey // 按 kid 索引的验证密钥（非对称算法）
+	signing *signingKey            // 当前签名密钥，为 nil 时使用 HS256
 }
 
 // 使用配置初始化JWT管理器
-func Init(cfg *Config) {
-	if cfg == nil {
-		cfg = DefaultConfig()
+func Init(cfg *Config) error {
+	m, err := NewJWTManager(cfg)
+	if err != nil {
+		return err
 	}
-	Manager = &JWTManager{config: cfg}
+	Manager = m
+	return nil
 }
 
 // 获取全局JWT管理器实例
@@ -71,11 +115,16 @@ func GetManager() *JWTManager {
 //
 // 返回:
 //   - *JWTManager: JWT管理器实例
-func NewJWTManager(config *Config) *JWTManager {
+//   - error: 密钥加载失败时返回错误
+func NewJWTManager(config *Config) (*JWTManager, error) {
 	if config == nil {
 		config = DefaultConfig()
 	}
-	return &JWTManager{config: config}
+	keys, signing, err := loadKeys(config)
+	if err != nil {
+		return nil, err
+	}
+	return &JWTManager{config: config, keys: keys, signing: signing}, nil
 }
 
 //	设置签名密钥
@@ -114,26 +163,81 @@ func (m *JWTManager) SetRefreshTokenDuration(duration time.Duration) {
 //   - string: 生成的token字符串
 //   - error: 错误信息
 func (m *JWTManager) GenerateToken(userID, username string, roles []string, extra map[string]interface{}) (string, error) {
-	if m.config.SecretKey == "" {
-		return "", ErrSecretKeyEmpty
+	return m.GenerateSessionToken("", userID, username, roles, extra)
+}
+
+//	生成绑定会话的访问令牌
+//
+// 参数:
+//   - sessionID: 会话ID，写入 sid 声明
+//   - userID: 用户ID
+//   - username: 用户名
+//   - roles: 用户角色列表
+//   - extra: 额外的自定义字段
+//
+// 返回:
+//   - string: 生成的token字符串
+//   - error: 错误信息
+func (m *JWTManager) GenerateSessionToken(sessionID, userID, username string, roles []string, extra map[string]interface{}) (string, error) {
+	return m.generateAccessToken(sessionID, userID, username, roles, extra, m.config.AccessTokenDuration)
+}
+
+//	生成代登录访问令牌（不签发刷新令牌）
+//
+// 参数:
+//   - sessionID: 会话ID，写入 sid 声明
+//   - userID: 被代登录的用户ID
+//   - username: 被代登录的用户名
+//   - roles: 被代登录用户的角色列表
+//   - actor: 实际操作人，写入 Extra 的 act 字段
+//   - duration: 有效期
+//
+// 返回:
+//   - string: 生成的token字符串
+//   - error: 错误信息
+func (m *JWTManager) GenerateImpersonationToken(sessionID, userID, username string, roles []string, actor *Actor, duration time.Duration) (string, error) {
+	extra := map[string]interface{}{
+		ExtraActor:        actor,
+		ExtraImpersonated: true,
 	}
+	return m.generateAccessToken(sessionID, userID, username, roles, extra, duration)
+}
 
+// generateAccessToken 生成指定有效期的访问令牌
+func (m *JWTManager) generateAccessToken(sessionID, userID, username string, roles []string, extra map[string]interface{}, duration time.Duration) (string, error) {
 	now := time.Now()
 	claims := &Claims{
-		UserID:   userID,
-		Username: username,
-		Roles:    roles,
-		Extra:    extra,
+		UserID:    userID,
+		Username:  username,
+		Roles:     roles,
+		Extra:     extra,
+		TokenType: TokenTypeAccess,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			Issuer:    m.config.Issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
-			ExpiresAt: jwt.NewNumericDate(now.Add(m.config.AccessTokenDuration)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.config.SecretKey))
+	return m.sign(claims)
+}
+
+// Impersonator 返回代登录令牌中的实际操作人，普通令牌返回 false
+func (c *Claims) Impersonator() (*Actor, bool) {
+	act, ok := c.Extra[ExtraActor].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	actor := &Actor{}
+	actor.UserID, _ = act["sub"].(string)
+	actor.Username, _ = act["username"].(string)
+	if actor.UserID == "" {
+		return nil, false
+	}
+	return actor, true
 }
 
 //	生成刷新令牌
@@ -145,14 +249,26 @@ func (m *JWTManager) GenerateToken(userID, username string, roles []string, extr
 //   - string: 生成的刷新token字符串
 //   - error: 错误信息
 func (m *JWTManager) GenerateRefreshToken(userID string) (string, error) {
-	if m.config.SecretKey == "" {
-		return "", ErrSecretKeyEmpty
-	}
+	return m.GenerateSessionRefreshToken("", userID)
+}
 
+//	生成绑定会话的刷新令牌
+//
+// 参数:
+//   - sessionID: 会话ID，写入 sid 声明
+//   - userID: 用户ID
+//
+// 返回:
+//   - string: 生成的刷新token字符串
+//   - error: 错误信息
+func (m *JWTManager) GenerateSessionRefreshToken(sessionID, userID string) (string, error) {
 	now := time.Now()
 	claims := &Claims{
-		UserID: userID,
+		UserID:    userID,
+		TokenType: TokenTypeRefresh,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			Issuer:    m.config.Issuer,
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(m.config.RefreshTokenDuration)),
@@ -160,8 +276,24 @@ func (m *JWTManager) GenerateRefreshToken(userID string) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.config.SecretKey))
+	return m.sign(claims)
+}
+
+//	签名令牌
+//
+// 非对称算法使用当前签名密钥并在头部写入 kid，否则使用 SecretKey 进行 HS256 签名
+func (m *JWTManager) sign(claims *Claims) (string, error) {
+	if m.signing == nil {
+		if m.config.SecretKey == "" {
+			return "", ErrSecretKeyEmpty
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(m.config.SecretKey))
+	}
+
+	token := jwt.NewWithClaims(m.signing.method, claims)
+	token.Header["kid"] = m.signing.id
+	return token.SignedString(m.signing.private)
 }
 
 //	同时生成访问令牌和刷新令牌
@@ -190,6 +322,33 @@ func (m *JWTManager) GenerateTokenPair(userID, username string, roles []string,
 	return accessToken, refreshToken, nil
 }
 
+//	同时生成绑定会话的访问令牌和刷新令牌
+//
+// 参数:
+//   - sessionID: 会话ID
+//   - userID: 用户ID
+//   - username: 用户名
+//   - roles: 用户角色列表
+//   - extra: 额外的自定义字段
+//
+// 返回:
+//   - accessToken: 访问令牌
+//   - refreshToken: 刷新令牌
+//   - error: 错误信息
+func (m *JWTManager) GenerateSessionTokenPair(sessionID, userID, username string, roles []string, extra map[string]interface{}) (accessToken, refreshToken string, err error) {
+	accessToken, err = m.GenerateSessionToken(sessionID, userID, username, roles, extra)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, err = m.GenerateSessionRefreshToken(sessionID, userID)
+	if err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
 //	解析并验证token
 //
 // 参数:
@@ -199,13 +358,7 @@ func (m *JWTManager) GenerateTokenPair(userID, username string, roles []string,
 //   - *Claims: 解析后的声明
 //   - error: 错误信息
 func (m *JWTManager) ParseToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// 验证签名方法
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrTokenInvalid
-		}
-		return []byte(m.config.SecretKey), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, m.keyFunc)
 
 	if err != nil {
 		// 详细的错误处理
@@ -228,6 +381,47 @@ func (m *JWTManager) ParseToken(tokenString string) (*Claims, error) {
 	return nil, ErrTokenInvalid
 }
 
+//	根据令牌头部选择验证密钥
+//
+// HS256 模式只接受 HMAC 签名；非对称模式按 kid 查找密钥，
+// 要求算法与密钥一致且密钥未超过验证期限，防止算法混淆攻击
+func (m *JWTManager) keyFunc(token *jwt.Token) (interface{}, error) {
+	if m.signing == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrTokenInvalid
+		}
+		return []byte(m.config.SecretKey), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys[kid]
+	if !ok || key.method.Alg() != token.Method.Alg() || key.expired(time.Now()) {
+		return nil, ErrTokenInvalid
+	}
+	return key.public, nil
+}
+
+//	获取用于验证令牌的公钥集合（JWKS）
+//
+// 返回:
+//   - *JWKSet: 未过期的公钥集合，HS256 模式下为空集合
+func (m *JWTManager) JWKS() *JWKSet {
+	set := &JWKSet{Keys: make([]JWK, 0, len(m.keys))}
+	now := time.Now()
+	for _, key := range m.keys {
+		if key.expired(now) {
+			continue
+		}
+		jwk, err := key.toJWK()
+		if err != nil {
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
 //	验证token是否有效
 //
 // 参数:
@@ -290,8 +484,13 @@ func (m *JWTManager) RefreshAccessToken(refreshToken, username string, roles []s
 		return "", err
 	}
 
-	// 使用刷新令牌中的用户ID生成新的访问令牌
-	return m.GenerateToken(claims.UserID, username, roles, extra)
+	// 只接受刷新令牌，防止用访问令牌换取新令牌
+	if claims.TokenType != TokenTypeRefresh {
+		return "", ErrNotRefreshToken
+	}
+
+	// 使用刷新令牌中的用户ID和会话ID生成新的访问令牌
+	return m.GenerateSessionToken(claims.SessionID, claims.UserID, username, roles, extra)
 }
 
 //	获取token中的所有声明
@@ -347,6 +546,21 @@ func GenerateTokenPair(userID, username string, roles []string, extra map[string
 	return Manager.GenerateTokenPair(userID, username, roles, extra)
 }
 
+// 使用全局管理器同时生成绑定会话的访问令牌和刷新令牌
+func GenerateSessionTokenPair(sessionID, userID, username string, roles []string, extra map[string]interface{}) (accessToken, refreshToken string, err error) {
+	return Manager.GenerateSessionTokenPair(sessionID, userID, username, roles, extra)
+}
+
+// 使用全局管理器生成代登录访问令牌
+func GenerateImpersonationToken(sessionID, userID, username string, roles []string, actor *Actor, duration time.Duration) (string, error) {
+	return Manager.GenerateImpersonationToken(sessionID, userID, username, roles, actor, duration)
+}
+
+// 使用全局管理器生成绑定会话的刷新令牌
+func GenerateSessionRefreshToken(sessionID, userID string) (string, error) {
+	return Manager.GenerateSessionRefreshToken(sessionID, userID)
+}
+
 // 使用全局管理器解析并验证token
 func ParseToken(tokenString string) (*Claims, error) {
 	return Manager.ParseToken(tokenString)
@@ -381,3 +595,8 @@ func GetClaims(tokenString string) (*Claims, error) {
 func HasRole(tokenString, role string) (bool, error) {
 	return Manager.HasRole(tokenString, role)
 }
+
+// 使用全局管理器获取公钥集合
+func JWKS() *JWKSet {
+	return Manager.JWKS()
+}
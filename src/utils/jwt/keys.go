@@ -0,0 +1,248 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// 签名算法
+const (
+	AlgorithmHS256 = "HS256" // 对称签名，使用 SecretKey（默认）
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+var (
+	ErrSigningKeyNotFound = errors.New("签名密钥不存在")
+	ErrUnsupportedKey     = errors.New("不支持的密钥类型")
+)
+
+// KeyConfig 非对称密钥配置
+// 只配置公钥的密钥仅用于验证，可在密钥轮换期间继续验证旧密钥签发的令牌
+type KeyConfig struct {
+	ID             string // 密钥ID，写入令牌头部的 kid
+	Algorithm      string // 签名算法，为空时使用 Config.Algorithm
+	PrivateKeyFile string // PEM 格式私钥文件（签名密钥必填）
+	PublicKeyFile  string // PEM 格式公钥文件，为空时从私钥推导
+	ExpiresAt      string // 停止验证的时间（RFC3339），为空表示长期有效
+}
+
+// signingKey 已加载的密钥
+type signingKey struct {
+	id        string
+	method    jwt.SigningMethod
+	private   crypto.PrivateKey // 仅用于验证的密钥为 nil
+	public    crypto.PublicKey
+	expiresAt time.Time // 零值表示长期有效
+}
+
+// expired 密钥是否已超过验证期限
+func (k *signingKey) expired(now time.Time) bool {
+	return !k.expiresAt.IsZero() && now.After(k.expiresAt)
+}
+
+// loadKeys 加载配置中的非对称密钥
+// 算法为 HS256 时返回空结果，继续使用 SecretKey 签名
+func loadKeys(cfg *Config) (map[string]*signingKey, *signingKey, error) {
+	if cfg.Algorithm == "" || cfg.Algorithm == AlgorithmHS256 {
+		return nil, nil, nil
+	}
+
+	keys := make(map[string]*signingKey, len(cfg.Keys))
+	for _, kc := range cfg.Keys {
+		if kc.ID == "" {
+			return nil, nil, errors.New("密钥ID（kid）不能为空")
+		}
+		if _, ok := keys[kc.ID]; ok {
+			return nil, nil, fmt.Errorf("密钥ID重复: %s", kc.ID)
+		}
+		alg := kc.Algorithm
+		if alg == "" {
+			alg = cfg.Algorithm
+		}
+		key, err := loadKey(kc, alg)
+		if err != nil {
+			return nil, nil, fmt.Errorf("加载密钥 %s 失败: %w", kc.ID, err)
+		}
+		keys[kc.ID] = key
+	}
+
+	signing, ok := keys[cfg.SigningKeyID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrSigningKeyNotFound, cfg.SigningKeyID)
+	}
+	if signing.private == nil {
+		return nil, nil, fmt.Errorf("签名密钥 %s 未配置私钥", cfg.SigningKeyID)
+	}
+	if signing.method.Alg() != cfg.Algorithm {
+		return nil, nil, fmt.Errorf("签名密钥 %s 的算法 %s 与配置的算法 %s 不一致", cfg.SigningKeyID, signing.method.Alg(), cfg.Algorithm)
+	}
+	return keys, signing, nil
+}
+
+// loadKey 从 PEM 文件加载单个密钥
+func loadKey(kc KeyConfig, alg string) (*signingKey, error) {
+	method := jwt.GetSigningMethod(alg)
+	if method == nil {
+		return nil, fmt.Errorf("不支持的签名算法: %s", alg)
+	}
+	if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+		return nil, fmt.Errorf("密钥列表不支持对称算法: %s", alg)
+	}
+
+	key := &signingKey{id: kc.ID, method: method}
+	if kc.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, kc.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("过期时间格式错误: %w", err)
+		}
+		key.expiresAt = t
+	}
+
+	if kc.PrivateKeyFile != "" {
+		data, err := os.ReadFile(kc.PrivateKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		private, err := parsePrivateKey(data)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, ErrUnsupportedKey
+		}
+		key.private = private
+		key.public = signer.Public()
+	}
+	if kc.PublicKeyFile != "" {
+		data, err := os.ReadFile(kc.PublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		public, err := parsePublicKey(data)
+		if err != nil {
+			return nil, err
+		}
+		key.public = public
+	}
+	if key.public == nil {
+		return nil, errors.New("未配置私钥或公钥")
+	}
+
+	if err := checkKeyType(method, key.public); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// checkKeyType 检查密钥类型与签名算法是否匹配
+func checkKeyType(method jwt.SigningMethod, public crypto.PublicKey) error {
+	switch m := method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		if _, ok := public.(*rsa.PublicKey); ok {
+			return nil
+		}
+	case *jwt.SigningMethodECDSA:
+		if pub, ok := public.(*ecdsa.PublicKey); ok && pub.Curve.Params().BitSize == m.CurveBits {
+			return nil
+		}
+	case *jwt.SigningMethodEd25519:
+		if _, ok := public.(ed25519.PublicKey); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: 与算法 %s 不匹配", ErrUnsupportedKey, method.Alg())
+}
+
+// parsePrivateKey 解析 PEM 格式私钥（支持 PKCS#8、PKCS#1、SEC 1）
+func parsePrivateKey(data []byte) (crypto.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("私钥不是有效的 PEM 格式")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// parsePublicKey 解析 PEM 格式公钥（支持 PKIX、PKCS#1）
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("公钥不是有效的 PEM 格式")
+	}
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// ==================== JWKS ====================
+
+// JWK 单个公钥（RFC 7517）
+type JWK struct {
+	Kty string `json:"kty"`           // 密钥类型：RSA、EC、OKP
+	Use string `json:"use"`           // 用途：sig
+	Alg string `json:"alg"`           // 签名算法
+	Kid string `json:"kid"`           // 密钥ID
+	N   string `json:"n,omitempty"`   // RSA 模数
+	E   string `json:"e,omitempty"`   // RSA 指数
+	Crv string `json:"crv,omitempty"` // 曲线：P-256、Ed25519
+	X   string `json:"x,omitempty"`   // EC / OKP 公钥 X 坐标
+	Y   string `json:"y,omitempty"`   // EC 公钥 Y 坐标
+}
+
+// JWKSet 公钥集合
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// toJWK 将公钥转换为 JWK
+func (k *signingKey) toJWK() (JWK, error) {
+	jwk := JWK{Use: "sig", Alg: k.method.Alg(), Kid: k.id}
+	enc := base64.RawURLEncoding
+
+	switch pub := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = enc.EncodeToString(pub.N.Bytes())
+		jwk.E = enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.Kty = "EC"
+		jwk.Crv = pub.Curve.Params().Name
+		jwk.X = enc.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = enc.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = enc.EncodeToString(pub)
+	default:
+		return JWK{}, ErrUnsupportedKey
+	}
+	return jwk, nil
+}
@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"hi-go/src/utils/logger"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FileMailer 文件邮件实现，不真正发送邮件
+// 邮件内容追加写入指定文件，未指定文件时输出到日志，便于开发和测试环境查看
+type FileMailer struct {
+	from string
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileMailer 创建文件邮件实例
+func NewFileMailer(from, path string) *FileMailer {
+	return &FileMailer{
+		from: from,
+		path: path,
+	}
+}
+
+// Send 写入邮件内容
+func (m *FileMailer) Send(ctx context.Context, msg *Message) error {
+	if m.path == "" {
+		logger.Info("邮件（未实际发送）",
+			zap.String("from", m.from),
+			zap.String("to", msg.To),
+			zap.String("subject", msg.Subject),
+			zap.String("body", msg.Body))
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(m.path), 0o755); err != nil {
+		return fmt.Errorf("创建邮件目录失败: %w", err)
+	}
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("打开邮件文件失败: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nFrom: %s\nTo: %s\nSubject: %s\n\n%s\n\n----------\n\n",
+		time.Now().Format(time.RFC3339), m.from, msg.To, msg.Subject, msg.Body)
+	return err
+}
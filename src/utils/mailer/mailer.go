@@ -0,0 +1,78 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// 邮件驱动
+const (
+	DriverSMTP = "smtp" // 通过 SMTP 服务器发送
+	DriverFile = "file" // 写入文件或日志，用于开发和测试环境
+)
+
+var (
+	ErrNotInitialized = errors.New("邮件服务未初始化")
+	ErrEmptyRecipient = errors.New("收件人不能为空")
+)
+
+// Message 邮件内容
+type Message struct {
+	To      string // 收件人地址
+	Subject string // 主题
+	Body    string // 正文（纯文本）
+}
+
+// Mailer 邮件发送接口
+type Mailer interface {
+	// Send 发送邮件
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Config 邮件配置
+type Config struct {
+	Driver   string // 驱动：smtp、file
+	From     string // 发件人地址
+	Host     string // SMTP 服务器地址
+	Port     int    // SMTP 端口
+	Username string // SMTP 用户名
+	Password string // SMTP 密码
+	FilePath string // file 驱动的输出文件，为空时输出到日志
+}
+
+// Default 全局邮件发送实例
+var Default Mailer
+
+// New 根据配置创建邮件发送实例
+func New(cfg *Config) (Mailer, error) {
+	switch cfg.Driver {
+	case DriverSMTP:
+		return NewSMTPMailer(cfg), nil
+	case DriverFile, "":
+		return NewFileMailer(cfg.From, cfg.FilePath), nil
+	default:
+		return nil, fmt.Errorf("不支持的邮件驱动: %s", cfg.Driver)
+	}
+}
+
+// Init 初始化全局邮件发送实例
+func Init(cfg *Config) error {
+	m, err := New(cfg)
+	if err != nil {
+		return err
+	}
+	Default = m
+	return nil
+}
+
+// Send 使用全局实例发送邮件
+func Send(ctx context.Context, msg *Message) error {
+	if Default == nil {
+		return ErrNotInitialized
+	}
+	if msg.To == "" {
+		return ErrEmptyRecipient
+	}
+	return Default.Send(ctx, msg)
+}
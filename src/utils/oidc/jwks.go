@@ -0,0 +1,124 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+// jsonWebKey JWKS 中的单个公钥（RFC 7517）
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// jsonWebKeySet JWKS 响应
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// publicKey 解析后的公钥
+type publicKey struct {
+	kid string
+	alg string // 可能为空，表示未限定算法
+	key interface{}
+}
+
+// keySet 身份提供方的签名公钥集合
+type keySet struct {
+	keys []publicKey
+}
+
+// find 按 kid 和算法查找公钥；令牌未携带 kid 且只有一个公钥时直接使用
+func (s *keySet) find(kid, alg string) (interface{}, bool) {
+	var candidates []publicKey
+	for _, k := range s.keys {
+		if k.alg != "" && k.alg != alg {
+			continue
+		}
+		if kid != "" && k.kid != kid {
+			continue
+		}
+		candidates = append(candidates, k)
+	}
+	if len(candidates) != 1 {
+		return nil, false
+	}
+	return candidates[0].key, true
+}
+
+// parseKeySet 解析 JWKS，跳过非签名用途和无法识别的公钥
+func parseKeySet(raw *jsonWebKeySet) *keySet {
+	set := &keySet{}
+	for _, jwk := range raw.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, ok := parseKey(&jwk)
+		if !ok {
+			continue
+		}
+		set.keys = append(set.keys, publicKey{kid: jwk.Kid, alg: jwk.Alg, key: key})
+	}
+	return set
+}
+
+// parseKey 将 JWK 转换为 Go 公钥
+func parseKey(jwk *jsonWebKey) (interface{}, bool) {
+	dec := base64.RawURLEncoding
+	switch jwk.Kty {
+	case "RSA":
+		n, err1 := dec.DecodeString(jwk.N)
+		e, err2 := dec.DecodeString(jwk.E)
+		if err1 != nil || err2 != nil || len(e) > 4 {
+			return nil, false
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, true
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, false
+		}
+		x, err1 := dec.DecodeString(jwk.X)
+		y, err2 := dec.DecodeString(jwk.Y)
+		if err1 != nil || err2 != nil {
+			return nil, false
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, true
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, false
+		}
+		x, err := dec.DecodeString(jwk.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, false
+		}
+		return ed25519.PublicKey(x), true
+	default:
+		return nil, false
+	}
+}
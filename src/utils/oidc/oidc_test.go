@@ -0,0 +1,223 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID    = "test-client"
+	testRedirectURL = "https://app.example.com/api/user/oauth/test/callback"
+	testCode        = "test-code"
+)
+
+// fakeIssuer 模拟身份提供方：发现文档、JWKS 和令牌端点
+type fakeIssuer struct {
+	*httptest.Server
+	key       *rsa.PrivateKey
+	challenge string        // 授权请求中的 code_challenge，令牌端点据此校验 code_verifier
+	claims    jwt.MapClaims // 令牌端点签发的 ID Token 内容
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeIssuer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(Discovery{
+			Issuer:                f.URL,
+			AuthorizationEndpoint: f.URL + "/authorize",
+			TokenEndpoint:         f.URL + "/token",
+			JWKSURI:               f.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jsonWebKeySet{Keys: []jsonWebKey{{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: "k1",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// 按 RFC 7636 校验 code_verifier 与授权请求中的 code_challenge 对应
+		if r.PostForm.Get("grant_type") != "authorization_code" ||
+			r.PostForm.Get("code") != testCode ||
+			r.PostForm.Get("client_id") != testClientID ||
+			r.PostForm.Get("redirect_uri") != testRedirectURL ||
+			f.challenge == "" ||
+			CodeChallenge(r.PostForm.Get("code_verifier")) != f.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(Token{
+			AccessToken: "access",
+			TokenType:   "Bearer",
+			IDToken:     f.sign(t, f.key, f.claims),
+			ExpiresIn:   3600,
+		})
+	})
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+	return f
+}
+
+// sign 使用 key 签发 RS256 令牌，kid 固定为 k1
+func (f *fakeIssuer) sign(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "k1"
+	raw, err := token.SignedString(key)
+	if err != nil {
+		t.Error(err)
+	}
+	return raw
+}
+
+// validClaims 返回一组能通过验证的 ID Token 内容
+func (f *fakeIssuer) validClaims(nonce string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":   f.URL,
+		"aud":   testClientID,
+		"sub":   "user-1",
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+		"nonce": nonce,
+		"email": "user@example.com",
+	}
+}
+
+func (f *fakeIssuer) provider() *Provider {
+	return NewProvider(&Config{
+		Issuer:      f.URL,
+		ClientID:    testClientID,
+		RedirectURL: testRedirectURL,
+		Scopes:      []string{"email"},
+	})
+}
+
+func TestAuthCodeExchangeWithPKCE(t *testing.T) {
+	f := newFakeIssuer(t)
+	p := f.provider()
+	ctx := context.Background()
+
+	verifier, err := RandomString()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL 失败: %v", err)
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if !strings.HasPrefix(authURL, f.URL+"/authorize?") {
+		t.Errorf("授权地址 = %s", authURL)
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") != CodeChallenge(verifier) {
+		t.Errorf("PKCE 参数错误: %v", q)
+	}
+	if q.Get("state") != "state-1" || q.Get("nonce") != "nonce-1" || q.Get("scope") != "openid email" {
+		t.Errorf("授权参数错误: %v", q)
+	}
+	f.challenge = q.Get("code_challenge")
+	f.claims = f.validClaims("nonce-1")
+
+	t.Run("校验码正确", func(t *testing.T) {
+		token, err := p.Exchange(ctx, testCode, verifier)
+		if err != nil {
+			t.Fatalf("Exchange 失败: %v", err)
+		}
+		claims, err := p.VerifyIDToken(ctx, token.IDToken, "nonce-1")
+		if err != nil {
+			t.Fatalf("VerifyIDToken 失败: %v", err)
+		}
+		if claims.Subject != "user-1" || claims.Email != "user@example.com" {
+			t.Errorf("claims = %+v", claims)
+		}
+	})
+
+	t.Run("校验码错误", func(t *testing.T) {
+		other, _ := RandomString()
+		if _, err := p.Exchange(ctx, testCode, other); !errors.Is(err, ErrExchangeFailed) {
+			t.Fatalf("err = %v，期望 ErrExchangeFailed", err)
+		}
+	})
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	f := newFakeIssuer(t)
+	p := f.provider()
+	ctx := context.Background()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		modify func(c jwt.MapClaims)
+		key    *rsa.PrivateKey
+		nonce  string
+		ok     bool
+	}{
+		{name: "有效", nonce: "n", ok: true},
+		{name: "签发方不一致", nonce: "n", modify: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "受众不一致", nonce: "n", modify: func(c jwt.MapClaims) { c["aud"] = "other-client" }},
+		{name: "受众列表包含客户端", nonce: "n", ok: true, modify: func(c jwt.MapClaims) { c["aud"] = []string{"other-client", testClientID} }},
+		{name: "已过期", nonce: "n", modify: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{name: "缺少过期时间", nonce: "n", modify: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "缺少 sub", nonce: "n", modify: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "nonce 不匹配", nonce: "other"},
+		{name: "签名公钥不匹配", nonce: "n", key: otherKey},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims := f.validClaims("n")
+			if tc.modify != nil {
+				tc.modify(claims)
+			}
+			key := f.key
+			if tc.key != nil {
+				key = tc.key
+			}
+
+			_, err := p.VerifyIDToken(ctx, f.sign(t, key, claims), tc.nonce)
+			if tc.ok && err != nil {
+				t.Fatalf("期望通过，err = %v", err)
+			}
+			if !tc.ok && !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("err = %v，期望 ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
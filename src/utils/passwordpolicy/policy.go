@@ -0,0 +1,257 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bcrypt 只使用密码的前 72 个字节，超出部分会被拒绝
+const bcryptMaxBytes = 72
+
+// 校验不通过的原因
+const (
+	CodeTooShort       = "too_short"       // 长度不足
+	CodeTooLong        = "too_long"        // 长度超限
+	CodeCharClasses    = "char_classes"    // 字符种类不足
+	CodeSimilarToUser  = "similar_to_user" // 与用户名、邮箱等个人信息过于相似
+	CodeCommonPassword = "common_password" // 常见或已泄露的密码
+)
+
+// Config 密码策略配置
+type Config struct {
+	MinLength      int    // 最小长度（字符数）
+	MaxLength      int    // 最大长度（字符数），不超过 bcrypt 的 72 字节限制
+	MinCharClasses int    // 至少包含的字符种类数（大写字母、小写字母、数字、符号），0 表示不限制
+	CheckUserInfo  bool   // 是否禁止与用户名、邮箱相似
+	BlocklistFile  string // 常见/已泄露密码列表文件，每行一个，# 开头为注释，为空表示不检查
+}
+
+// Violation 单条校验失败详情
+type Violation struct {
+	Field   string `json:"field"`   // 字段名
+	Code    string `json:"code"`    // 失败原因
+	Message string `json:"message"` // 提示信息
+}
+
+// Error 密码不符合策略
+type Error struct {
+	Violations []Violation
+}
+
+// Error 合并全部提示信息
+func (e *Error) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Message)
+	}
+	return "密码不符合要求：" + strings.Join(messages, "；")
+}
+
+// Policy 密码策略
+type Policy struct {
+	config    Config
+	blocklist map[string]struct{}
+}
+
+// Default 全局密码策略，未初始化时只检查长度
+var Default = &Policy{config: Config{MinLength: 6, MaxLength: bcryptMaxBytes}}
+
+// New 根据配置创建密码策略，配置了列表文件时一次性加载到内存
+func New(cfg *Config) (*Policy, error) {
+	p := &Policy{config: *cfg}
+	if p.config.MaxLength <= 0 || p.config.MaxLength > bcryptMaxBytes {
+		p.config.MaxLength = bcryptMaxBytes
+	}
+	if p.config.MinLength > p.config.MaxLength {
+		return nil, fmt.Errorf("密码最小长度 %d 超过最大长度 %d", p.config.MinLength, p.config.MaxLength)
+	}
+	if p.config.MinCharClasses > 4 {
+		p.config.MinCharClasses = 4
+	}
+
+	if cfg.BlocklistFile != "" {
+		blocklist, err := loadBlocklist(cfg.BlocklistFile)
+		if err != nil {
+			return nil, fmt.Errorf("加载密码黑名单失败: %w", err)
+		}
+		p.blocklist = blocklist
+	}
+	return p, nil
+}
+
+// Init 初始化全局密码策略
+func Init(cfg *Config) error {
+	p, err := New(cfg)
+	if err != nil {
+		return err
+	}
+	Default = p
+	return nil
+}
+
+// BlocklistSize 已加载的黑名单密码数量
+func (p *Policy) BlocklistSize() int {
+	return len(p.blocklist)
+}
+
+// Validate 校验密码，userInputs 为用户名、邮箱等个人信息（可为空）
+// 不符合策略时返回 *Error，包含全部失败项
+func (p *Policy) Validate(password string, userInputs ...string) error {
+	var violations []Violation
+	add := func(code, message string) {
+		violations = append(violations, Violation{Field: "password", Code: code, Message: message})
+	}
+
+	// 1. 长度
+	length := utf8.RuneCountInString(password)
+	if length < p.config.MinLength {
+		add(CodeTooShort, fmt.Sprintf("长度至少 %d 位", p.config.MinLength))
+	}
+	if length > p.config.MaxLength || len(password) > bcryptMaxBytes {
+		add(CodeTooLong, fmt.Sprintf("长度不能超过 %d 位", p.config.MaxLength))
+	}
+
+	// 2. 字符种类
+	if p.config.MinCharClasses > 0 && charClasses(password) < p.config.MinCharClasses {
+		add(CodeCharClasses, fmt.Sprintf("需包含大写字母、小写字母、数字、符号中的至少 %d 种", p.config.MinCharClasses))
+	}
+
+	// 3. 与个人信息相似
+	if p.config.CheckUserInfo && similarToUserInfo(password, userInputs) {
+		add(CodeSimilarToUser, "不能与用户名或邮箱相似")
+	}
+
+	// 4. 常见或已泄露的密码
+	if p.isBlocked(password) {
+		add(CodeCommonPassword, "该密码过于常见或已在泄露数据中出现，请更换")
+	}
+
+	if len(violations) > 0 {
+		return &Error{Violations: violations}
+	}
+	return nil
+}
+
+// Validate 使用全局策略校验密码
+func Validate(password string, userInputs ...string) error {
+	return Default.Validate(password, userInputs...)
+}
+
+// isBlocked 密码是否在黑名单中
+// 同时检查去掉末尾数字和符号后的形式，拦截 password123!、qwerty2024 这类变体
+func (p *Policy) isBlocked(password string) bool {
+	if len(p.blocklist) == 0 {
+		return false
+	}
+	normalized := strings.ToLower(password)
+	if _, ok := p.blocklist[normalized]; ok {
+		return true
+	}
+	trimmed := strings.TrimRightFunc(normalized, func(r rune) bool {
+		return unicode.IsDigit(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+	if trimmed != normalized && trimmed != "" {
+		_, ok := p.blocklist[trimmed]
+		return ok
+	}
+	return false
+}
+
+// charClasses 统计密码包含的字符种类数
+func charClasses(password string) int {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	count := 0
+	for _, ok := range []bool{upper, lower, digit, symbol} {
+		if ok {
+			count++
+		}
+	}
+	return count
+}
+
+// similarToUserInfo 密码是否与个人信息相似：互相包含、包含倒序、或编辑距离不超过 2
+// 邮箱只比较 @ 之前的部分，少于 3 个字符的信息不参与比较
+func similarToUserInfo(password string, userInputs []string) bool {
+	pwd := strings.ToLower(password)
+	for _, input := range userInputs {
+		input, _, _ = strings.Cut(strings.ToLower(input), "@")
+		if utf8.RuneCountInString(input) < 3 {
+			continue
+		}
+		if strings.Contains(pwd, input) || strings.Contains(input, pwd) ||
+			strings.Contains(pwd, reverse(input)) || levenshtein(pwd, input) <= 2 {
+			return true
+		}
+	}
+	return false
+}
+
+// reverse 反转字符串
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// levenshtein 计算两个字符串的编辑距离
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// loadBlocklist 加载黑名单文件，统一转为小写
+func loadBlocklist(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	blocklist := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		blocklist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return blocklist, nil
+}
@@ -148,6 +148,22 @@ func Get(ctx context.Context, key string) (string, error) {
 	return val, err
 }
 
+// 获取值并删除该键（原子操作），键不存在时返回 ErrKeyNotFound
+func GetDel(ctx context.Context, key string) (string, error) {
+	if key == "" {
+		return "", ErrEmptyKey
+	}
+	if Client == nil {
+		return "", ErrClientNil
+	}
+
+	val, err := Client.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return "", ErrKeyNotFound
+	}
+	return val, err
+}
+
 // 设置新值并返回旧值
 func GetSet(ctx context.Context, key string, value interface{}) (string, error) {
 	if key == "" {
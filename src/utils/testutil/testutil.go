@@ -0,0 +1,77 @@
+// Package testutil 单元测试辅助：用内存 SQLite 和 miniredis 替换全局的 MySQL、Redis 连接
+package testutil
+
+import (
+	"hi-go/src/config"
+	"hi-go/src/utils/mysql"
+	"hi-go/src/utils/redis"
+	"hi-go/src/utils/snowflake"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/glebarez/sqlite"
+	goredis "github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// SetupConfig 使用测试配置替换全局配置，测试结束后恢复，并初始化雪花ID生成器
+// fn 可为 nil，用于修改个别配置项
+func SetupConfig(t testing.TB, fn func(cfg *config.AppConfig)) *config.AppConfig {
+	t.Helper()
+	cfg := &config.AppConfig{
+		Business: config.BusinessConfig{DefaultPageSize: 20, MaxPageSize: 100},
+	}
+	if fn != nil {
+		fn(cfg)
+	}
+
+	old := config.Config
+	config.Config = cfg
+	t.Cleanup(func() { config.Config = old })
+
+	if snowflake.DefaultGenerator == nil {
+		if err := snowflake.Init(1); err != nil {
+			t.Fatalf("初始化雪花ID生成器失败: %v", err)
+		}
+	}
+	return cfg
+}
+
+// SetupDB 创建内存 SQLite 数据库并迁移 models，替换 mysql.Database，测试结束后恢复
+func SetupDB(t testing.TB, models ...interface{}) *gorm.DB {
+	t.Helper()
+	// 每个测试使用独立的内存库；共享缓存保证连接池中的多个连接看到同一个库
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared&_pragma=foreign_keys(1)"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("打开 SQLite 失败: %v", err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("迁移数据表失败: %v", err)
+	}
+
+	sqlDB, _ := db.DB()
+	old := mysql.Database
+	mysql.Database = db
+	t.Cleanup(func() {
+		mysql.Database = old
+		sqlDB.Close()
+	})
+	return db
+}
+
+// SetupRedis 启动 miniredis 并替换 redis.Client，测试结束后恢复
+func SetupRedis(t testing.TB) *miniredis.Miniredis {
+	t.Helper()
+	mr := miniredis.RunT(t)
+
+	old := redis.Client
+	redis.Client = goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() {
+		redis.Client.Close()
+		redis.Client = old
+	})
+	return mr
+}